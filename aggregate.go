@@ -0,0 +1,57 @@
+package cache
+
+import "cmp"
+
+// Number is the set of numeric types SumBy can add up.
+type Number interface {
+	~int | ~int8 | ~int16 | ~int32 | ~int64 |
+		~uint | ~uint8 | ~uint16 | ~uint32 | ~uint64 | ~uintptr |
+		~float32 | ~float64
+}
+
+// SumBy returns the sum of fn over all live items, computed in a single
+// pass under the read lock without copying the data.
+func SumBy[T any, N Number](c *Cache[T], fn func(T) N) N {
+	var sum N
+	c.each(func(_ string, v T) {
+		sum += fn(v)
+	})
+	return sum
+}
+
+// MinBy returns the smallest fn(value) over all live items together with
+// the key of the item producing it. ok is false for an empty cache. Ties
+// resolve to an arbitrary one of the tied keys.
+func MinBy[T any, N cmp.Ordered](c *Cache[T], fn func(T) N) (min N, key string, ok bool) {
+	c.each(func(k string, v T) {
+		if n := fn(v); !ok || n < min {
+			min, key, ok = n, k, true
+		}
+	})
+	return min, key, ok
+}
+
+// MaxBy returns the largest fn(value) over all live items together with
+// the key of the item producing it. ok is false for an empty cache.
+func MaxBy[T any, N cmp.Ordered](c *Cache[T], fn func(T) N) (max N, key string, ok bool) {
+	c.each(func(k string, v T) {
+		if n := fn(v); !ok || n > max {
+			max, key, ok = n, k, true
+		}
+	})
+	return max, key, ok
+}
+
+// each calls fn for every live item under the read lock, passing the
+// stored (uncloned) value.
+func (c *Cache[T]) each(fn func(key string, value T)) {
+	now := c.clock.Now()
+	c.clearExpiredData(now)
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	for k, e := range c.data {
+		if !e.expired(now) {
+			fn(k, e.val())
+		}
+	}
+}
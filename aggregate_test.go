@@ -0,0 +1,58 @@
+package cache_test
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/TheOrchestraX/cache"
+)
+
+type quota struct {
+	Limit int64
+	Ratio float64
+}
+
+func TestAggregatesEmpty(t *testing.T) {
+	c, _ := newTestCache(t, map[string]quota{})
+	limit := func(q quota) int64 { return q.Limit }
+	if s := cache.SumBy(c, limit); s != 0 {
+		t.Fatalf("SumBy = %d, want 0", s)
+	}
+	if v, k, ok := cache.MinBy(c, limit); ok {
+		t.Fatalf("MinBy = %d, %q, true; want not ok", v, k)
+	}
+	if v, k, ok := cache.MaxBy(c, limit); ok {
+		t.Fatalf("MaxBy = %d, %q, true; want not ok", v, k)
+	}
+}
+
+func TestAggregatesFixture(t *testing.T) {
+	// The limits sum to just below MaxInt64, so any overflow or double
+	// counting would show.
+	c, clk := newTestCache(t, map[string]quota{
+		"acme":    {Limit: math.MaxInt64 / 2, Ratio: 0.5},
+		"globex":  {Limit: math.MaxInt64/2 - 10, Ratio: 0.25},
+		"initech": {Limit: 9, Ratio: 1.5},
+	})
+	limit := func(q quota) int64 { return q.Limit }
+	if s, want := cache.SumBy(c, limit), int64(math.MaxInt64-2); s != want {
+		t.Fatalf("SumBy = %d, want %d", s, want)
+	}
+	if s := cache.SumBy(c, func(q quota) float64 { return q.Ratio }); s != 2.25 {
+		t.Fatalf("SumBy ratio = %v, want 2.25", s)
+	}
+	if v, k, ok := cache.MinBy(c, limit); !ok || k != "initech" || v != 9 {
+		t.Fatalf("MinBy = %d, %q, %v; want 9, initech, true", v, k, ok)
+	}
+	if v, k, ok := cache.MaxBy(c, func(q quota) float64 { return q.Ratio }); !ok || k != "initech" || v != 1.5 {
+		t.Fatalf("MaxBy = %v, %q, %v; want 1.5, initech, true", v, k, ok)
+	}
+
+	// Expired items take no part.
+	c.AddWithTTL("hooli", quota{Limit: 1}, time.Minute)
+	clk.Advance(2 * time.Minute)
+	if v, k, _ := cache.MinBy(c, limit); k != "initech" {
+		t.Fatalf("MinBy after expiry = %d, %q; want initech", v, k)
+	}
+}
@@ -0,0 +1,45 @@
+package cache
+
+import "slices"
+
+// WithAliases makes every item reachable under the extra keys returned by
+// fn, in addition to its primary key. Aliases are kept in an alias→primary
+// indirection map, so values are stored (and counted by GetAll and Stats)
+// once. Get, GetAndTouch, Touch, TTL, Expire, Delete, GetVersioned,
+// AddIfVersion, GetAt and GetFrom accept aliases; deleting through an alias
+// removes the item and all of its aliases.
+//
+// A primary key always takes precedence over an alias of the same name.
+// If several items claim one alias, it resolves to the item with the
+// lexicographically smallest primary key.
+func WithAliases[T any](fn func(T) []string) Option[T] {
+	return func(c *Cache[T]) {
+		c.aliases = &index[T]{keysFn: fn, byKey: make(map[string]map[string]struct{})}
+	}
+}
+
+// resolveLocked maps key to the primary key it refers to, returning key
+// unchanged when it is a primary key or not a known alias. The caller must
+// hold c.mu.
+func (c *Cache[T]) resolveLocked(key string) string {
+	if c.aliases == nil {
+		return key
+	}
+	if _, ok := c.data[key]; ok {
+		return key
+	}
+	primaries, ok := c.aliases.byKey[key]
+	if !ok {
+		return key
+	}
+	if len(primaries) == 1 {
+		for p := range primaries {
+			return p
+		}
+	}
+	keys := make([]string, 0, len(primaries))
+	for p := range primaries {
+		keys = append(keys, p)
+	}
+	return slices.Min(keys)
+}
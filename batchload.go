@@ -0,0 +1,198 @@
+package cache
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// batchCall is one in-flight batch key loader call, shared by the
+// GetOrLoadMany callers waiting on its keys.
+type batchCall[T any] struct {
+	done chan struct{}
+	vals map[string]T // set before done is closed
+	err  error
+}
+
+// WithBatchKeyLoader sets the loader GetOrLoadMany uses to fetch missing
+// keys from the origin in one call. It returns the items it found; keys it
+// omits are treated as not found. The keys it returns are normalized like
+// any other key, and those that were not asked for are ignored.
+func WithBatchKeyLoader[T any](fn func(ctx context.Context, keys []string) (map[string]T, error)) Option[T] {
+	return func(c *Cache[T]) {
+		c.batchLoader = fn
+	}
+}
+
+// WithNegativeTTL makes GetOrLoadMany remember keys the batch key loader
+// did not find for d, so that repeated requests for them do not reach the
+// origin. DeleteExpired also drops lapsed entries of this kind.
+func WithNegativeTTL[T any](d time.Duration) Option[T] {
+	return func(c *Cache[T]) {
+		c.negativeTTL = d
+	}
+}
+
+// GetOrLoadMany returns the items for keys, serving hits from memory and
+// fetching all misses with a single call to the WithBatchKeyLoader loader.
+// Misses already being fetched for a concurrent caller are waited for
+// rather than requested again. Loaded items are stored as if by Add. Keys
+// that are neither cached nor found by the loader are absent from the
+// result. If the loader fails, or ctx is done while waiting, the items
+// gathered so far are returned with the error.
+func (c *Cache[T]) GetOrLoadMany(ctx context.Context, keys []string) (map[string]T, error) {
+	result := make(map[string]T, len(keys))
+	seen := make(map[string]struct{}, len(keys))
+	var missing []string
+	now := c.clock.Now()
+	for _, k := range keys {
+		k = c.normKey(k)
+		if _, dup := seen[k]; dup {
+			continue
+		}
+		seen[k] = struct{}{}
+		if v, ok := c.Get(k); ok {
+			result[k] = v
+		} else if !c.knownMissing(k, now) {
+			missing = append(missing, k)
+		}
+	}
+	if len(missing) == 0 {
+		return result, nil
+	}
+	if c.batchLoader == nil {
+		return result, ErrNoBatchLoader
+	}
+
+	// Claim the misses nobody is fetching yet; wait for the rest.
+	own := &batchCall[T]{done: make(chan struct{})}
+	var owned []string
+	waits := make(map[*batchCall[T]][]string)
+	c.batchMu.Lock()
+	if c.inflight == nil {
+		c.inflight = make(map[string]*batchCall[T])
+	}
+	for _, k := range missing {
+		if call, ok := c.inflight[k]; ok {
+			waits[call] = append(waits[call], k)
+			continue
+		}
+		c.inflight[k] = own
+		owned = append(owned, k)
+	}
+	c.batchMu.Unlock()
+
+	var firstErr error
+	if len(owned) > 0 {
+		own.vals, own.err = c.callBatchLoader(ctx, owned)
+		if own.err == nil {
+			own.vals, own.err = c.validItems(c.requestedOnly(owned, own.vals))
+		}
+		if own.err == nil {
+			c.storeBatch(owned, own.vals)
+		}
+		c.batchMu.Lock()
+		for _, k := range owned {
+			delete(c.inflight, k)
+		}
+		c.batchMu.Unlock()
+		close(own.done)
+		waits[own] = owned
+	}
+	for call, ks := range waits {
+		select {
+		case <-call.done:
+		case <-ctx.Done():
+			if firstErr == nil {
+				firstErr = ctx.Err()
+			}
+			continue
+		}
+		if call.err != nil {
+			if firstErr == nil {
+				firstErr = call.err
+			}
+			continue
+		}
+		for _, k := range ks {
+			if v, ok := call.vals[k]; ok {
+				result[k] = c.output(v)
+			}
+		}
+	}
+	return result, firstErr
+}
+
+// callBatchLoader invokes the batch key loader, converting a panic into an
+// error wrapping ErrLoaderPanic.
+func (c *Cache[T]) callBatchLoader(ctx context.Context, keys []string) (result map[string]T, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("%w: %v", ErrLoaderPanic, r)
+		}
+	}()
+	return c.batchLoader(ctx, keys)
+}
+
+// requestedOnly returns the items of vals, a batch key loader's result,
+// under their normalized keys, dropping any key that was not requested so
+// a loader cannot store items nobody asked for.
+func (c *Cache[T]) requestedOnly(requested []string, vals map[string]T) map[string]T {
+	want := make(map[string]struct{}, len(requested))
+	for _, k := range requested {
+		want[k] = struct{}{}
+	}
+	kept := make(map[string]T, len(vals))
+	for k, v := range vals {
+		k = c.normKey(k)
+		if _, ok := want[k]; ok {
+			kept[k] = v
+		}
+	}
+	return kept
+}
+
+// storeBatch stores the items a batch key loader returned for requested,
+// and remembers the requested keys it did not find if WithNegativeTTL is
+// set.
+func (c *Cache[T]) storeBatch(requested []string, vals map[string]T) {
+	if c.rejectFrozen("store") {
+		return
+	}
+	now := c.clock.Now()
+	c.mu.Lock()
+	ttl := c.addTTL()
+	muts := make([]mutation[T], 0, len(vals))
+	for k, v := range vals {
+		muts = append(muts, c.setNotifiedLocked(k, v, c.manualEntry(v, expiryFor(now, ttl)), now))
+	}
+	if c.negativeTTL > 0 {
+		for _, k := range requested {
+			if _, ok := vals[k]; ok {
+				delete(c.negative, k)
+				continue
+			}
+			if c.negative == nil {
+				c.negative = make(map[string]int64)
+			}
+			c.negative[k] = expiryFor(now, c.negativeTTL)
+		}
+	}
+	muts = append(muts, c.evictLocked()...)
+	c.writeBehindStageLocked(muts)
+	h := c.hooks
+	c.mu.Unlock()
+	c.notify(h, muts)
+}
+
+// knownMissing reports whether key was recently not found by the batch key
+// loader, per WithNegativeTTL.
+func (c *Cache[T]) knownMissing(key string, now time.Time) bool {
+	if c.negativeTTL <= 0 {
+		return false
+	}
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	exp, ok := c.negative[key]
+	return ok && now.UnixNano() < exp
+}
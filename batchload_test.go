@@ -0,0 +1,38 @@
+package cache_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/TheOrchestraX/cache"
+)
+
+func TestGetOrLoadManyStoresOnlyRequestedKeys(t *testing.T) {
+	var asked []string
+	c, _ := newTestCache(t, map[string]int{"cached": 0},
+		cache.WithKeyNormalizer[int](strings.ToLower),
+		cache.WithBatchKeyLoader(func(ctx context.Context, keys []string) (map[string]int, error) {
+			asked = append(asked, keys...)
+			// Upper-case keys as the origin spells them, plus one that
+			// was never requested.
+			return map[string]int{"A": 1, "B": 2, "EXTRA": 3}, nil
+		}))
+	got, err := c.GetOrLoadMany(context.Background(), []string{"A", "b", "cached", "missing"})
+	if err != nil {
+		t.Fatalf("GetOrLoadMany: %v", err)
+	}
+	if len(asked) != 3 {
+		t.Fatalf("loader asked for %v, want the three misses", asked)
+	}
+	if len(got) != 3 || got["a"] != 1 || got["b"] != 2 || got["cached"] != 0 {
+		t.Fatalf("GetOrLoadMany = %v, want a:1 b:2 cached:0", got)
+	}
+	if v := mustGet(t, c, "A"); v != 1 {
+		t.Fatalf("Get(A) = %d, want 1", v)
+	}
+	mustMiss(t, c, "extra")
+	if n := c.Len(); n != 3 {
+		t.Fatalf("Len = %d, want 3", n)
+	}
+}
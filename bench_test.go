@@ -0,0 +1,228 @@
+package cache_test
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/TheOrchestraX/cache"
+)
+
+// benchItems returns n items keyed "k0" to "k<n-1>", with their keys.
+func benchItems(n int) (map[string]int, []string) {
+	items := make(map[string]int, n)
+	keys := make([]string, n)
+	for i := range n {
+		keys[i] = fmt.Sprintf("k%d", i)
+		items[keys[i]] = i
+	}
+	return items, keys
+}
+
+// newBenchCache returns a silent, manual-only cache loaded with items.
+func newBenchCache[T any](b *testing.B, items map[string]T, opts ...cache.Option[T]) *cache.Cache[T] {
+	b.Helper()
+	opts = append([]cache.Option[T]{cache.WithLogLevel[T](cache.LogSilent)}, opts...)
+	c := cache.NewCache(func() (map[string]T, error) { return maps.Clone(items), nil }, 0, opts...)
+	if err := c.Load(); err != nil {
+		b.Fatal(err)
+	}
+	return c
+}
+
+// storageModes are the item layouts compared by the storage benchmarks.
+var storageModes = []struct {
+	name string
+	opts []cache.Option[int]
+}{
+	{"map", nil},
+	{"chunked", []cache.Option[int]{cache.WithChunkedStorage[int](1024)}},
+}
+
+// BenchmarkWriteDuringGetAll measures Add latency while another goroutine
+// exports the whole cache with GetAllInto in a loop, as a metrics exporter
+// would, and reports the 99th percentile. Without chunked storage writers
+// queue behind each export's read lock.
+func BenchmarkWriteDuringGetAll(b *testing.B) {
+	items, keys := benchItems(200_000)
+	for _, mode := range storageModes {
+		b.Run(mode.name, func(b *testing.B) {
+			c := newBenchCache(b, items, mode.opts...)
+			stop := make(chan struct{})
+			running := make(chan struct{})
+			var wg sync.WaitGroup
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				dst := make(map[string]int, len(items))
+				c.GetAllInto(dst)
+				close(running)
+				for {
+					select {
+					case <-stop:
+						return
+					default:
+						c.GetAllInto(dst)
+					}
+				}
+			}()
+			<-running
+			lat := make([]time.Duration, b.N)
+			b.ResetTimer()
+			for i := range b.N {
+				start := time.Now()
+				c.Add(keys[i%len(keys)], i)
+				lat[i] = time.Since(start)
+			}
+			b.StopTimer()
+			close(stop)
+			wg.Wait()
+			slices.Sort(lat)
+			b.ReportMetric(float64(lat[len(lat)*99/100].Nanoseconds()), "p99-ns")
+		})
+	}
+}
+
+// BenchmarkGetAll measures a full export of an idle cache.
+func BenchmarkGetAll(b *testing.B) {
+	items, _ := benchItems(200_000)
+	for _, mode := range storageModes {
+		b.Run(mode.name, func(b *testing.B) {
+			c := newBenchCache(b, items, mode.opts...)
+			dst := make(map[string]int, len(items))
+			b.ResetTimer()
+			for range b.N {
+				c.GetAllInto(dst)
+			}
+		})
+	}
+}
+
+// spin is a CPU-bound predicate costing about a microsecond per call.
+func spin(v int) bool {
+	x := uint64(v)
+	for range 500 {
+		x ^= x << 13
+		x ^= x >> 7
+		x ^= x << 17
+	}
+	return x%7 == 0
+}
+
+// BenchmarkFindParallel runs a CPU-bound predicate over 100,000 items with
+// a growing worker pool, to show FindParallel scaling with the cores
+// available; compare ns/op across the worker counts.
+func BenchmarkFindParallel(b *testing.B) {
+	items, _ := benchItems(100_000)
+	c := newBenchCache(b, items)
+	for _, workers := range []int{1, 2, 4, 8} {
+		b.Run(fmt.Sprintf("workers=%d", workers), func(b *testing.B) {
+			for range b.N {
+				c.FindParallel(spin, workers)
+			}
+		})
+	}
+}
+
+// BenchmarkGetMiss looks up absent keys from parallel goroutines, as a
+// probing workload would, with and without WithBloomFilter.
+func BenchmarkGetMiss(b *testing.B) {
+	items, _ := benchItems(100_000)
+	_, probes := benchItems(200_000)
+	probes = probes[100_000:]
+	for _, mode := range []struct {
+		name string
+		opts []cache.Option[int]
+	}{
+		{"plain", nil},
+		{"bloom", []cache.Option[int]{cache.WithBloomFilter[int](len(items), 0.01)}},
+	} {
+		b.Run(mode.name, func(b *testing.B) {
+			c := newBenchCache(b, items, mode.opts...)
+			b.ResetTimer()
+			b.RunParallel(func(pb *testing.PB) {
+				i := 0
+				for pb.Next() {
+					c.Get(probes[i%len(probes)])
+					i++
+				}
+			})
+		})
+	}
+}
+
+// BenchmarkGetAllInto compares allocs/op of GetAll, which allocates a map
+// per call, with GetAllInto refilling one map across calls.
+func BenchmarkGetAllInto(b *testing.B) {
+	items, _ := benchItems(10_000)
+	c := newBenchCache(b, items)
+	b.Run("fresh", func(b *testing.B) {
+		b.ReportAllocs()
+		for range b.N {
+			c.GetAll()
+		}
+	})
+	b.Run("reused", func(b *testing.B) {
+		dst := make(map[string]int, len(items))
+		b.ReportAllocs()
+		b.ResetTimer()
+		for range b.N {
+			c.GetAllInto(dst)
+		}
+	})
+}
+
+// BenchmarkFindInto compares Find with FindInto appending to a reused
+// buffer, which should allocate nothing.
+func BenchmarkFindInto(b *testing.B) {
+	items, _ := benchItems(1000)
+	c := newBenchCache(b, items)
+	rare := func(v int) bool { return v%100 == 0 }
+	b.Run("fresh", func(b *testing.B) {
+		b.ReportAllocs()
+		for range b.N {
+			c.Find(rare)
+		}
+	})
+	b.Run("reused", func(b *testing.B) {
+		buf := c.FindInto(nil, rare)
+		b.ReportAllocs()
+		b.ResetTimer()
+		for range b.N {
+			buf = c.FindInto(buf[:0], rare)
+		}
+	})
+}
+
+// BenchmarkFindSeq consumes every match of predicates matching a growing
+// share of 100,000 items. FindSeq's B/op stays flat as matches grow,
+// where Find's grows with the result.
+func BenchmarkFindSeq(b *testing.B) {
+	items, _ := benchItems(100_000)
+	c := newBenchCache(b, items)
+	for _, every := range []int{1000, 10, 1} {
+		match := func(v int) bool { return v%every == 0 }
+		b.Run(fmt.Sprintf("Find/matches=%d", len(items)/every), func(b *testing.B) {
+			b.ReportAllocs()
+			for range b.N {
+				sum := 0
+				for _, v := range c.Find(match) {
+					sum += v
+				}
+			}
+		})
+		b.Run(fmt.Sprintf("FindSeq/matches=%d", len(items)/every), func(b *testing.B) {
+			b.ReportAllocs()
+			for range b.N {
+				sum := 0
+				c.FindSeq(match)(func(_ string, v int) bool {
+					sum += v
+					return true
+				})
+			}
+		})
+	}
+}
@@ -0,0 +1,116 @@
+package cache
+
+import (
+	"hash/maphash"
+	"math"
+	"sync/atomic"
+)
+
+// bloomSeed is shared by all filters; membership is only ever tested
+// against filters built in the same process.
+var bloomSeed = maphash.MakeSeed()
+
+// bloomFilter is a fixed-size Bloom filter whose bits are updated
+// atomically, so lookups need no lock while inserts proceed.
+type bloomFilter struct {
+	bits []atomic.Uint64
+	m    uint64 // number of bits
+	k    uint64 // number of hash functions
+}
+
+// newBloomFilter sizes a filter for n items at false-positive rate p.
+func newBloomFilter(n int, p float64) *bloomFilter {
+	n = max(n, 1)
+	if p <= 0 || p >= 1 {
+		p = 0.01
+	}
+	m := uint64(math.Ceil(-float64(n) * math.Log(p) / (math.Ln2 * math.Ln2)))
+	m = max(m, 64)
+	k := uint64(math.Round(float64(m) / float64(n) * math.Ln2))
+	k = max(k, 1)
+	return &bloomFilter{
+		bits: make([]atomic.Uint64, (m+63)/64),
+		m:    m,
+		k:    k,
+	}
+}
+
+// add inserts key into the filter.
+func (b *bloomFilter) add(key string) {
+	h1, h2 := bloomHashes(key)
+	for i := uint64(0); i < b.k; i++ {
+		bit := (h1 + i*h2) % b.m
+		word, mask := &b.bits[bit/64], uint64(1)<<(bit%64)
+		for {
+			old := word.Load()
+			if old&mask != 0 || word.CompareAndSwap(old, old|mask) {
+				break
+			}
+		}
+	}
+}
+
+// mayContain reports false only if key was definitely never added.
+func (b *bloomFilter) mayContain(key string) bool {
+	h1, h2 := bloomHashes(key)
+	for i := uint64(0); i < b.k; i++ {
+		bit := (h1 + i*h2) % b.m
+		if b.bits[bit/64].Load()&(1<<(bit%64)) == 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// bloomHashes derives the two base hashes for double hashing.
+func bloomHashes(key string) (uint64, uint64) {
+	h := maphash.String(bloomSeed, key)
+	return h, (h >> 32) | 1
+}
+
+// WithBloomFilter puts a Bloom filter in front of Get so that lookups of
+// keys that are definitely absent return without taking the read lock. The
+// filter is sized for expectedItems (or the loaded item count, if larger)
+// at false-positive rate fpRate, rebuilt from the key set on every Load,
+// and updated by Add. Deleted keys linger in the filter as false positives
+// until the next rebuild, which only costs them the regular locked lookup.
+func WithBloomFilter[T any](expectedItems int, fpRate float64) Option[T] {
+	return func(c *Cache[T]) {
+		c.bloomItems = expectedItems
+		c.bloomFPRate = fpRate
+		c.bloom.Store(newBloomFilter(expectedItems, fpRate))
+	}
+}
+
+// rebuildBloomLocked replaces the Bloom filter with one built from the
+// keys (and aliases) in data. The caller must hold c.mu for writing.
+func (c *Cache[T]) rebuildBloomLocked(data map[string]*entry[T]) {
+	if c.bloom.Load() == nil {
+		return
+	}
+	bf := newBloomFilter(max(c.bloomItems, len(data)), c.bloomFPRate)
+	for k, e := range data {
+		bf.add(k)
+		if c.aliases != nil {
+			for _, a := range c.aliases.keysFn(e.val()) {
+				bf.add(a)
+			}
+		}
+	}
+	c.bloom.Store(bf)
+}
+
+// addBloomLocked records key and its aliases in the Bloom filter, if any.
+// The caller must hold c.mu for writing.
+func (c *Cache[T]) addBloomLocked(key string, e *entry[T]) {
+	bf := c.bloom.Load()
+	if bf == nil {
+		return
+	}
+	bf.add(key)
+	if c.aliases != nil {
+		for _, a := range c.aliases.keysFn(e.val()) {
+			bf.add(a)
+		}
+	}
+}
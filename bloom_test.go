@@ -0,0 +1,61 @@
+package cache_test
+
+import (
+	"fmt"
+	"maps"
+	"testing"
+
+	"github.com/TheOrchestraX/cache"
+)
+
+func TestBloomFilterHasNoFalseNegatives(t *testing.T) {
+	// Loaded well past expectedItems, so the rebuild must size up.
+	items, keys := benchItems(5000)
+	c, _ := newTestCache(t, items, cache.WithBloomFilter[int](10, 0.01))
+	for _, k := range keys {
+		if v := mustGet(t, c, k); v != items[k] {
+			t.Fatalf("Get(%q) = %d, want %d", k, v, items[k])
+		}
+	}
+	c.Add("added", 1)
+	mustGet(t, c, "added")
+}
+
+func TestBloomFilterMisses(t *testing.T) {
+	c, _ := newTestCache(t, map[string]int{"a": 1}, cache.WithBloomFilter[int](100, 0.01))
+	for i := range 100 {
+		mustMiss(t, c, fmt.Sprintf("absent%d", i))
+	}
+	if s := c.Stats(); s.Misses != 100 {
+		t.Fatalf("Misses = %d, want 100", s.Misses)
+	}
+	c.Delete("a")
+	mustMiss(t, c, "a")
+}
+
+func TestBloomFilterRebuiltOnLoad(t *testing.T) {
+	data := map[string]int{"a": 1}
+	c := cache.NewCache(func() (map[string]int, error) { return maps.Clone(data), nil }, 0,
+		cache.WithLogLevel[int](cache.LogSilent), cache.WithBloomFilter[int](100, 0.01))
+	if err := c.Load(); err != nil {
+		t.Fatal(err)
+	}
+	mustMiss(t, c, "b")
+	data = map[string]int{"b": 2}
+	if err := c.Load(); err != nil {
+		t.Fatal(err)
+	}
+	if v := mustGet(t, c, "b"); v != 2 {
+		t.Fatalf("Get(b) = %d, want 2", v)
+	}
+	mustMiss(t, c, "a")
+}
+
+func TestBloomFilterAliases(t *testing.T) {
+	c, _ := newTestCache(t, map[string]int{"a": 1},
+		cache.WithAliases(func(v int) []string { return []string{fmt.Sprintf("id%d", v)} }),
+		cache.WithBloomFilter[int](100, 0.01))
+	mustGet(t, c, "id1")
+	c.Add("b", 2)
+	mustGet(t, c, "id2")
+}
@@ -0,0 +1,51 @@
+package cache
+
+// WithBootstrapData supplies a fallback data set, such as a small default
+// compiled into the binary with embed, for a service to start degraded
+// rather than empty. fn is consulted at most once: when a load fails
+// before any load has succeeded. Its items are installed as by Prime, so
+// Health still reports the cache as never loaded, with Freshness
+// Bootstrapped, and the first successful load replaces them wholesale. An
+// error from fn is logged and the cache stays as it was.
+func WithBootstrapData[T any](fn func() (map[string]T, error)) Option[T] {
+	return func(c *Cache[T]) {
+		c.bootstrap = fn
+	}
+}
+
+// bootstrapAfterFailure installs the WithBootstrapData fallback if a load
+// has just failed and none has ever succeeded. It runs fn only once.
+func (c *Cache[T]) bootstrapAfterFailure() {
+	c.mu.Lock()
+	fn := c.bootstrap
+	if fn == nil || !c.lastLoaded.IsZero() || c.frozen.Load() {
+		c.mu.Unlock()
+		return
+	}
+	c.bootstrap = nil
+	c.mu.Unlock()
+
+	items, err := fn()
+	if err == nil {
+		items, err = c.validItems(items)
+	}
+	if err != nil {
+		c.logf(LogErrorsOnly, "%s bootstrap data failed: %v", c.label(), err)
+		return
+	}
+	data := c.loadEntries(items)
+	c.mu.Lock()
+	if !c.lastLoaded.IsZero() || c.frozen.Load() {
+		// A load succeeded, or the cache was frozen, meanwhile.
+		c.mu.Unlock()
+		return
+	}
+	c.stripTombstonedLocked(data)
+	c.replaceLocked(data)
+	c.bootstrapped = true
+	evicted := c.evictLocked()
+	h := c.hooks
+	c.mu.Unlock()
+	c.notify(h, evicted)
+	c.logf(LogErrorsOnly, "%s first load failed; serving %d bootstrap items", c.label(), len(data))
+}
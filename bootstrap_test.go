@@ -0,0 +1,71 @@
+package cache_test
+
+import (
+	"errors"
+	"maps"
+	"testing"
+
+	"github.com/TheOrchestraX/cache"
+)
+
+func TestBootstrapUntilLoaderRecovers(t *testing.T) {
+	loadErr := errors.New("database down")
+	bootstraps := 0
+	c := cache.NewCache(func() (map[string]int, error) {
+		if loadErr != nil {
+			return nil, loadErr
+		}
+		return map[string]int{"real": 1}, nil
+	}, 0,
+		cache.WithLogLevel[int](cache.LogSilent),
+		cache.WithBootstrapData(func() (map[string]int, error) {
+			bootstraps++
+			return map[string]int{"default": 0, "real": -1}, nil
+		}))
+
+	if err := c.Load(); !errors.Is(err, loadErr) {
+		t.Fatalf("Load error = %v, want %v", err, loadErr)
+	}
+	if got, want := c.GetAll(), map[string]int{"default": 0, "real": -1}; !maps.Equal(got, want) {
+		t.Fatalf("GetAll = %v, want the bootstrap data %v", got, want)
+	}
+	h := c.Health()
+	if h.Loaded || h.Freshness != cache.Bootstrapped || h.ConsecutiveFailures != 1 {
+		t.Fatalf("Health = %+v, want not loaded, Bootstrapped, one failure", h)
+	}
+
+	c.Load() // fails again; the bootstrap data is not re-read
+	if bootstraps != 1 {
+		t.Fatalf("bootstrap data read %d times, want once", bootstraps)
+	}
+
+	loadErr = nil
+	if err := c.Load(); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := c.GetAll(), map[string]int{"real": 1}; !maps.Equal(got, want) {
+		t.Fatalf("GetAll = %v, want %v replacing the bootstrap data", got, want)
+	}
+	if h := c.Health(); !h.Loaded || h.Freshness != cache.Fresh {
+		t.Fatalf("Health = %+v, want loaded and Fresh", h)
+	}
+
+	// Later failures keep the real data.
+	loadErr = errors.New("down again")
+	c.Load()
+	if bootstraps != 1 || mustGet(t, c, "real") != 1 {
+		t.Fatalf("bootstrap data consulted after a successful load")
+	}
+}
+
+func TestBootstrapNotConsultedAfterSuccess(t *testing.T) {
+	c, _ := newTestCache(t, map[string]int{"real": 1},
+		cache.WithBootstrapData(func() (map[string]int, error) {
+			t.Error("bootstrap data consulted though the first load succeeded")
+			return nil, nil
+		}))
+	if h := c.Health(); !h.Loaded || h.Freshness != cache.Fresh {
+		t.Fatalf("Health = %+v, want loaded and Fresh", h)
+	}
+	mustGet(t, c, "real")
+}
@@ -1,8 +1,12 @@
 package cache
 
 import (
-	"log"
+	"context"
+	"fmt"
+	"maps"
+	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 )
 
@@ -12,148 +16,1149 @@ import (
 // It swaps in the entire map atomically on each reload.
 
 type Cache[T any] struct {
-	loader   func() (map[string]T, error)
-	interval time.Duration
-	mu       sync.RWMutex
-	data     map[string]T
-	ticker   *time.Ticker
-	quit     chan struct{}
+	loader      func(ctx context.Context) (map[string]T, error) // guarded by mu; see loaders
+	stream      StreamLoader[T]
+	interval    time.Duration
+	mu          sync.RWMutex
+	data        map[string]*entry[T]
+	run         *reloadRun // the running auto-reload, nil when stopped
+	lastRun     *reloadRun // the most recently started auto-reload
+	nextAt      time.Time  // see ScheduleNextReload
+	minDelay    time.Duration
+	schedule    func(after time.Time) time.Time
+	startDelay  time.Duration
+	startJitter bool // startDelay is the bound of a random delay
+	loadOnStart bool // see WithLoadOnStart
+	staleAfter  time.Duration
+	slowAfter   time.Duration
+	slowFactor  float64
+	reloadTimes reloadHistory
+	pinned      map[string]struct{}
+
+	maxDataAge   time.Duration
+	expirePolicy ExpirePolicy
+	dataCleared  bool // ClearData has dropped the expired data
+	tombstones   map[string]int64
+	journal      *journal            // see WithJournal
+	keyNorm      func(string) string // see WithKeyNormalizer
+	pending      *Pending[T]         // the open prepared reload, if any
+	nilResultErr bool                // see WithNilResultError
+	partitions   *partitions[T]      // see WithPartitionedLoader
+	loadSeq      uint64              // successful loads; see Pending.Commit
+	dims         *dimensions         // see WithStatsDimension
+	historyDepth int                 // see WithHistoryDepth
+	stable       bool                // see WithStableIteration
+	suspended    bool                // a fatal load error; see Fatal
+	wb           *writeBehind[T]     // see WithWriteBehind
+	id           uint64              // construction order; see SnapshotTogether
+	history      []historyRecord[T]  // oldest first
+
+	janitor         *janitorRun // the running janitor, nil when stopped
+	lastJanitor     *janitorRun // the most recently started janitor
+	janitorInterval time.Duration
+
+	transform     func(key string, raw T) (T, error)
+	validator     func(key string, value T) error
+	rejectNil     bool
+	invalidPolicy InvalidPolicy
+
+	shadow     *shadowLoader[T]
+	shadowBusy atomic.Bool // a shadow run is in progress
+	frozen     atomic.Bool // see Freeze
+
+	batchLoader func(ctx context.Context, keys []string) (map[string]T, error)
+	negativeTTL time.Duration
+	negative    map[string]int64 // keys the batch loader missed; see knownMissing
+	batchMu     sync.Mutex
+	inflight    map[string]*batchCall[T]
+	clock       Clock
+	name        string
+	logLevel    LogLevel
+	failures    int // consecutive failed loads
+	lastErr     error
+	lastLoaded  time.Time
+	lastAttempt time.Time // end of the last load, successful or not
+	stats       counters
+	statsSince  time.Time
+
+	slidingTTL       time.Duration
+	defaultTTL       time.Duration
+	defaultTTLOnLoad bool
+
+	prefixIndex  *sortedKeys
+	indexes      map[string]*index[T]
+	aliases      *index[T]
+	valueIndex   *index[T] // see WithValueHasher
+	bloom        atomic.Pointer[bloomFilter]
+	bloomItems   int
+	bloomFPRate  float64
+	lru          *lru[T]
+	seed         map[string]T                 // WithSeedData, installed once options are applied
+	bootstrap    func() (map[string]T, error) // see WithBootstrapData; nil once used
+	bootstrapped bool                         // the contents are bootstrap data
+	comp         *compression[T]
+	compStats    compressionStats
+	chunkSize    int
+	store        *chunkStore[T] // see WithChunkedStorage
+	gaugeFn      func(name string, value float64)
+	minReload    time.Duration
+	coalesce     bool
+	throttleMu   sync.Mutex
+	nextReload   *pendingReload
+	loadStamp    uint64 // stamped on entries by setLocked; see loadChunked
+
+	// generation advances on every change to the contents; derived data
+	// such as the cached sorted key order compares against it.
+	generation atomic.Uint64
+	sortedMu   sync.Mutex
+	sorted     []string
+	sortedGen  uint64
+
+	sizeWatched  atomic.Bool // an OnSizeChange hook or gauge func is set
+	sizeMu       sync.Mutex  // held while reporting; see sizeChanged
+	sizeDirty    atomic.Bool
+	reportedSize int
+	hooks        hooks[T]
+	cloner       func(T) T
+	equal        func(a, b T) bool
+	cloneOnAdd   bool
+	keyLocks     [keyLockStripes]sync.Mutex
+
+	// shared is set once a Snapshot references data; the next in-place
+	// mutation copies the map first.
+	shared atomic.Bool
 }
 
 // NewCache constructs a Cache for type T. interval defines how often
-// AutoReload triggers. The initial data map is empty.
-func NewCache[T any](loader func() (map[string]T, error), interval time.Duration) *Cache[T] {
-	return &Cache[T]{
+// AutoReload triggers; an interval <= 0 makes the cache manual-only, loaded
+// solely by explicit calls. The initial data map is empty.
+func NewCache[T any](loader func() (map[string]T, error), interval time.Duration, opts ...Option[T]) *Cache[T] {
+	var load func(context.Context) (map[string]T, error)
+	if loader != nil {
+		load = func(context.Context) (map[string]T, error) { return loader() }
+	}
+	return newCache(load, interval, opts)
+}
+
+// NewCacheWithContext is NewCache for loaders that honour cancellation.
+// The context passed to loader is cancelled when StopAutoReload stops the
+// reload it belongs to, or comes from the caller of LoadContext.
+func NewCacheWithContext[T any](loader func(ctx context.Context) (map[string]T, error), interval time.Duration, opts ...Option[T]) *Cache[T] {
+	return newCache(loader, interval, opts)
+}
+
+// newCache holds the construction shared by the exported constructors.
+func newCache[T any](loader func(context.Context) (map[string]T, error), interval time.Duration, opts []Option[T]) *Cache[T] {
+	c := &Cache[T]{
 		loader:   loader,
 		interval: interval,
-		data:     make(map[string]T),
-		quit:     make(chan struct{}),
+		data:     make(map[string]*entry[T]),
+		minDelay: defaultMinReloadDelay,
+		clock:    realClock{},
+		logLevel: LogInfo,
+		id:       cacheIDs.Add(1),
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	c.statsSince = c.clock.Now()
+	if c.seed != nil {
+		c.Prime(c.seed)
+		c.seed = nil
 	}
+	return c
 }
 
 // Load invokes the loader function and, on success, swaps in the new map.
-func (c *Cache[T]) Load() {
-	result, err := c.loader()
+// On failure the previous data is kept and the loader's error is returned.
+func (c *Cache[T]) Load() error {
+	return c.LoadContext(context.Background())
+}
+
+// LoadContext is Load with a context for the loader. If ctx is cancelled
+// before the new data is swapped in, the result is discarded, even if the
+// loader ignored ctx and succeeded, and ctx's error is returned without
+// counting as a failed load.
+func (c *Cache[T]) LoadContext(ctx context.Context) error {
+	if c.rejectFrozen("load") {
+		return ErrFrozen
+	}
+	c.loadStarted()
+	start := c.clock.Now()
+	shadow := c.startShadow()
+	err := c.load(ctx)
+	c.finishShadow(shadow, err)
+	if ctx.Err() == nil {
+		c.observeReload(c.clock.Now().Sub(start))
+	}
+	return err
+}
+
+// load runs one load for LoadContext.
+func (c *Cache[T]) load(ctx context.Context) error {
+	if c.chunkSize > 0 {
+		return c.loadChunked(ctx)
+	}
+	data, err := c.fetch(ctx)
+	if ctx.Err() != nil {
+		return c.loadCancelled(ctx)
+	}
+	if err != nil {
+		return c.loadFailed(err)
+	}
+	// Checking ctx under the write lock means a swap either completes
+	// before StopAutoReload cancels it or does not happen at all.
+	if !c.install(data, func() bool { return ctx.Err() == nil }) {
+		if ctx.Err() == nil {
+			return c.loadAbandoned(ErrFrozen) // frozen while the loader ran
+		}
+		return c.loadCancelled(ctx)
+	}
+	return nil
+}
+
+// loadCancelled logs and returns the error of a load abandoned because ctx
+// was cancelled, notifying OnLoadError hooks via loadAbandoned.
+func (c *Cache[T]) loadCancelled(ctx context.Context) error {
+	c.logf(LogDebug, "%s load cancelled: %v", c.label(), ctx.Err())
+	return c.loadAbandoned(ctx.Err())
+}
+
+// SetAll replaces the entire contents with items in one atomic swap, as if
+// a loader had returned them: readers never see an empty or partial cache,
+// the load is recorded in Health and Stats, and OnReload hooks fire. items
+// is copied, so the caller may reuse it afterwards.
+func (c *Cache[T]) SetAll(items map[string]T) {
+	items, err := c.validItems(items)
 	if err != nil {
-		log.Println("Cache load error:", err)
+		c.loadFailed(err)
 		return
 	}
+	c.install(c.loadEntries(items), nil)
+}
+
+// ReplaceIf is SetAll guarded by accept, which is called under the write
+// lock with the current live items and the candidate. The swap happens only
+// if accept returns true, so the check and the swap cannot race with other
+// writers. accept must not call back into the cache. ReplaceIf reports
+// whether the swap happened.
+func (c *Cache[T]) ReplaceIf(candidate map[string]T, accept func(current, candidate map[string]T) bool) bool {
+	valid, err := c.validItems(candidate)
+	if err != nil {
+		c.loadFailed(err)
+		return false
+	}
+	data := c.loadEntries(valid)
+	return c.install(data, func() bool {
+		now := c.clock.Now()
+		current := make(map[string]T, len(c.data))
+		for k, e := range c.data {
+			if !e.expired(now) {
+				current[k] = c.output(e.val())
+			}
+		}
+		return accept(current, candidate)
+	})
+}
+
+// loadEntries builds the entries for items as a load would.
+func (c *Cache[T]) loadEntries(items map[string]T) map[string]*entry[T] {
+	items = c.normalizeItems(items)
+	exp := c.loadExpiry()
+	data := make(map[string]*entry[T], len(items))
+	for k, v := range items {
+		data[k] = c.makeEntry(v, exp)
+	}
+	return data
+}
+
+// install swaps in data as the result of a successful load, then notifies
+// and logs. If accept is non-nil it is called first under the write lock,
+// and nothing happens unless it returns true. Nothing happens either while
+// the cache is frozen. install reports whether data was installed.
+func (c *Cache[T]) install(data map[string]*entry[T], accept func() bool) bool {
+	c.mu.Lock()
+	if accept != nil && !accept() {
+		c.mu.Unlock()
+		return false
+	}
+	if c.frozen.Load() {
+		c.mu.Unlock()
+		c.rejectFrozen("load")
+		return false
+	}
+	old := c.data
+	c.stripTombstonedLocked(data)
+	kept := c.keepPinnedLocked(data)
+	var changes []mutation[T]
+	if c.watchesLoadsLocked() {
+		now := c.clock.Now()
+		for k, e := range data {
+			changes = c.loadChangeLocked(changes, k, old[k], e, now)
+		}
+	}
+	indexTime := c.replaceLocked(data)
+	c.loadSucceededLocked()
+	indexed := len(c.indexes) > 0
+	evicted := c.evictLocked()
+	c.recordHistoryLocked()
+	h := c.hooks
+	c.mu.Unlock()
+	c.gauge("consecutive_failures", 0)
+	c.notifyChanges(h, changes)
+	c.notify(h, evicted)
+	for _, fn := range h.onReload {
+		fn(len(data))
+	}
+	if len(kept) > 0 {
+		c.logf(LogErrorsOnly, "%s reload dropped pinned keys %q; keeping their old values", c.label(), kept)
+	}
+
+	// The old map is no longer written to once swapped out, so the diff
+	// can be computed without holding the lock.
+	diff := diffData(old, data, c.equal)
+	level := LogInfo
+	if diff.empty() {
+		level = LogDebug
+	}
+	if indexed {
+		c.logf(level, "[%s] %s reloaded (%d items, %s, indexes rebuilt in %s)", c.clock.Now().Format(time.RFC3339), c.label(), len(data), diff, indexTime)
+		return true
+	}
+	c.logf(level, "[%s] %s reloaded (%d items, %s)", c.clock.Now().Format(time.RFC3339), c.label(), len(data), diff)
+	return true
+}
+
+// Name returns the name given via WithName, or "" for unnamed caches.
+func (c *Cache[T]) Name() string {
+	return c.name
+}
+
+// loadFailed records a failed load, notifies OnLoadError hooks and returns
+// err.
+func (c *Cache[T]) loadFailed(err error) error {
 	c.mu.Lock()
-	c.data = result
+	c.failures++
+	c.lastErr = err
+	c.stats.loadErrors.Add(1)
+	if IsFatal(err) {
+		c.suspendLocked(err)
+	}
+	failures := c.failures
+	c.lastAttempt = c.clock.Now()
+	h := c.hooks
 	c.mu.Unlock()
-	log.Printf("[%s] Cache reloaded (%d items)", time.Now().Format(time.RFC3339), len(result))
+	c.logf(LogErrorsOnly, "%s load error: %v", c.label(), err)
+	c.gauge("consecutive_failures", float64(failures))
+	c.bootstrapAfterFailure()
+	for _, fn := range h.onLoadError {
+		fn(err, failures)
+	}
+	c.clearExpiredData(c.clock.Now())
+	return err
 }
 
-// Reload is an alias for Load, to explicitly reload on demand.
-func (c *Cache[T]) Reload() {
-	c.Load()
+// loadSucceededLocked records a successful load. The caller must hold c.mu
+// for writing.
+func (c *Cache[T]) loadSucceededLocked() {
+	c.failures = 0
+	c.lastErr = nil
+	c.stats.loads.Add(1)
+	c.loadSeq++
+	c.lastLoaded = c.clock.Now()
+	c.lastAttempt = c.lastLoaded
+	c.dataCleared = false
+	c.bootstrapped = false
+	c.resumeLocked()
+}
+
+// fetch runs the loader and builds the entries of the replacement map.
+func (c *Cache[T]) fetch(ctx context.Context) (map[string]*entry[T], error) {
+	if _, stream := c.loaders(); stream != nil {
+		return c.fetchStream(ctx)
+	}
+	result, err := c.callLoader(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if result, err = c.validItems(result); err != nil {
+		return nil, err
+	}
+	return c.loadEntries(result), nil
 }
 
-// StartAutoReload spins up a ticker to call Load() every interval.
+// loadExpiry returns the expiry stamped on entries produced by a load.
+func (c *Cache[T]) loadExpiry() int64 {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return expiryFor(c.clock.Now(), c.loadTTL())
+}
+
+// callLoader invokes the loader, converting a panic into an error wrapping
+// ErrLoaderPanic.
+func (c *Cache[T]) callLoader(ctx context.Context) (result map[string]T, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("%w: %v", ErrLoaderPanic, r)
+		}
+	}()
+	load, _ := c.loaders()
+	return c.checkResult(load(ctx))
+}
+
+// Reload loads on demand, like Load. If WithMinReloadInterval is set, calls
+// arriving too soon after the previous load are throttled.
+func (c *Cache[T]) Reload() error {
+	if c.minReload > 0 {
+		return c.throttledReload()
+	}
+	return c.Load()
+}
+
+// StartAutoReload spins up a goroutine to call Load() every interval, or at
+// the time given to ScheduleNextReload. The first reload is delayed by
+// WithStartDelay or WithRandomStartDelay, if set; WithLoadOnStart adds an
+// immediate load on top. For a manual-only cache,
+// whose interval is <= 0, it logs and does nothing. The janitor set up by
+// WithJanitorInterval is started too, even for a manual-only cache.
 func (c *Cache[T]) StartAutoReload() {
+	c.StartAutoReloadAfter(c.firstReloadDelay())
+}
+
+// StartAutoReloadAfter is StartAutoReload with the first reload delayed by
+// d instead of the usual wait; later reloads follow the normal schedule.
+// Group.StartAllStaggered uses it to spread caches out.
+func (c *Cache[T]) StartAutoReloadAfter(d time.Duration) {
 	c.mu.Lock()
 	defer c.mu.Unlock()
-	if c.ticker != nil {
+	c.startJanitorLocked()
+	if c.run != nil {
 		return // already running
 	}
-	c.ticker = time.NewTicker(c.interval)
-	go func() {
-		for {
-			select {
-			case <-c.ticker.C:
+	if c.interval <= 0 && c.schedule == nil {
+		c.logf(LogInfo, "%s has no reload interval (%s); not starting auto-reload", c.label(), c.interval)
+		return
+	}
+	if d <= 0 {
+		d = c.nextReloadDelayLocked()
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	run := &reloadRun{
+		ctx:     ctx,
+		cancel:  cancel,
+		trigger: make(chan chan struct{}),
+		resched: make(chan struct{}, 1),
+		done:    make(chan struct{}),
+	}
+	prev := c.lastRun
+	c.run, c.lastRun = run, run
+	go c.reloadLoop(run, c.clock.NewTimer(d), prev, c.loadOnStart)
+}
+
+// reloadRun is the state owned by one auto-reload goroutine. Each start
+// creates a fresh one, so a goroutine that is still winding down after a
+// stop never shares channels with its successor.
+type reloadRun struct {
+	ctx     context.Context // cancelled by stopLocked
+	cancel  context.CancelFunc
+	trigger chan chan struct{} // see TriggerReload
+	resched chan struct{}      // wakes the loop to re-arm its timer
+	done    chan struct{}      // closed once the goroutine has exited
+}
+
+// reloadLoop is the auto-reload goroutine for run. It owns timer, re-arming
+// it after every reload and whenever the schedule changes, until run is
+// stopped. It first waits for prev, the previous run, to exit, so that at
+// most one loop is ever active however quickly the cache is stopped and
+// started again. With loadNow it loads once before waiting on timer.
+func (c *Cache[T]) reloadLoop(run *reloadRun, timer Timer, prev *reloadRun, loadNow bool) {
+	defer close(run.done)
+	defer timer.Stop()
+	if prev != nil {
+		<-prev.done
+	}
+	ctx := run.ctx
+	if loadNow && ctx.Err() == nil {
+		c.mu.RLock()
+		suspended := c.suspended
+		c.mu.RUnlock()
+		if !suspended {
+			c.LoadContext(ctx)
+		}
+	}
+	for {
+		select {
+		case <-timer.C():
+			if ctx.Err() != nil {
+				return
+			}
+			c.mu.Lock()
+			c.nextAt = time.Time{}
+			suspended := c.suspended
+			c.mu.Unlock()
+			if !suspended {
+				c.LoadContext(ctx)
+			}
+			timer.Reset(c.nextReloadDelay())
+		case done := <-run.trigger:
+			if ctx.Err() != nil {
+				// Stopped while the caller was handing over; load for it
+				// as TriggerReload does without a running loop.
 				c.Load()
-			case <-c.quit:
-				c.ticker.Stop()
+				close(done)
 				return
 			}
+			c.LoadContext(ctx)
+			close(done)
+		case <-run.resched:
+			if !timer.Stop() {
+				select {
+				case <-timer.C():
+				default:
+				}
+			}
+			timer.Reset(c.nextReloadDelay())
+		case <-ctx.Done():
+			return
 		}
-	}()
+	}
 }
 
-// StopAutoReload stops the periodic reload and cleans up resources.
+// TriggerReload asks the auto-reload goroutine to reload now, as if its
+// timer had fired, and returns a channel that is closed once that load and
+// its hooks have completed. Without a running auto-reload it loads
+// synchronously. It exists mainly so tests can make reloads deterministic;
+// see cachetest.TriggerReload.
+func (c *Cache[T]) TriggerReload() <-chan struct{} {
+	done := make(chan struct{})
+	c.mu.RLock()
+	run := c.run
+	c.mu.RUnlock()
+	if run != nil {
+		select {
+		case run.trigger <- done:
+			return done
+		case <-run.ctx.Done():
+		}
+	}
+	c.Load()
+	close(done)
+	return done
+}
+
+// StopAutoReload stops the periodic reload and the janitor, and cleans up
+// resources. It is safe to call repeatedly, and auto-reload may be started
+// again afterwards. It does not wait for a reload in progress to return;
+// see StopAndWait.
 func (c *Cache[T]) StopAutoReload() {
 	c.mu.Lock()
 	defer c.mu.Unlock()
-	if c.ticker != nil {
-		c.ticker.Stop()
-		c.ticker = nil
+	c.stopLocked()
+	c.stopJanitorLocked()
+}
+
+// stopLocked stops the auto-reload goroutine, if running. The caller must
+// hold c.mu for writing.
+func (c *Cache[T]) stopLocked() {
+	if c.run == nil {
+		return // not running
+	}
+	c.run.cancel()
+	c.run = nil
+}
+
+// IsAutoReloading reports whether the auto-reload goroutine is running: it
+// was started, has not been stopped, and has not exited. A manual-only
+// cache, on which StartAutoReload does nothing, reports false.
+func (c *Cache[T]) IsAutoReloading() bool {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.autoReloadingLocked()
+}
+
+// autoReloadingLocked implements IsAutoReloading. The caller must hold
+// c.mu.
+func (c *Cache[T]) autoReloadingLocked() bool {
+	if c.run == nil || c.run.ctx.Err() != nil {
+		return false
+	}
+	select {
+	case <-c.run.done:
+		return false
+	default:
+		return true
 	}
-	close(c.quit)
 }
 
-// SetInterval updates the reload interval at runtime.
-func (c *Cache[T]) SetInterval(interval time.Duration) {
+// StopAndWait stops auto-reload like StopAutoReload, then blocks until the
+// reload goroutine and the janitor have exited, including any load the
+// former was running, or until ctx is done, in which case ctx's error is
+// returned; it does not wait for either if it was never started. It then
+// drains any WithWriteBehind queue, returning FlushWriteBehind's error.
+func (c *Cache[T]) StopAndWait(ctx context.Context) error {
+	c.mu.Lock()
+	c.stopLocked()
+	c.stopJanitorLocked()
+	last, lastJanitor := c.lastRun, c.lastJanitor
+	c.mu.Unlock()
+	// Each run waits for its predecessor before exiting, so the last run
+	// finishing means all have.
+	if last != nil {
+		select {
+		case <-last.done:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+	if lastJanitor != nil {
+		select {
+		case <-lastJanitor.done:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+	return c.FlushWriteBehind(ctx)
+}
+
+// SetInterval updates the reload interval at runtime. An interval <= 0
+// makes the cache manual-only, stopping auto-reload if it is running. It
+// fails with ErrScheduleSet for caches reloading on a WithSchedule
+// schedule, which have no interval.
+func (c *Cache[T]) SetInterval(interval time.Duration) error {
 	c.mu.Lock()
 	defer c.mu.Unlock()
+	if c.schedule != nil {
+		return ErrScheduleSet
+	}
 	c.interval = interval
-	if c.ticker != nil {
-		c.ticker.Stop()
-		c.ticker = time.NewTicker(c.interval)
+	if c.run == nil {
+		return nil
+	}
+	if interval <= 0 {
+		c.stopLocked()
+		return nil
 	}
+	c.rescheduleLocked()
+	return nil
 }
 
 // Add inserts or updates a single item in the cache under the given key.
+// The item expires after the default TTL, if one is configured.
 func (c *Cache[T]) Add(key string, value T) {
+	c.Set(key, value)
+}
+
+// Set is Add reporting what it did: the value it replaced, if the key held
+// a live item, and whether the insert pushed the cache over its
+// WithMaxEntries limit and so evicted another item. A value rejected by
+// WithTransform, WithValueValidator or WithRejectNil is logged and not
+// stored.
+func (c *Cache[T]) Set(key string, value T) (previous T, replaced bool, evicted bool) {
+	key = c.normKey(key)
+	value, ok := c.validWrite(key, value)
+	if !ok {
+		return previous, false, false
+	}
+	return c.set(key, value)
+}
+
+// AddE is Add returning an error wrapping ErrInvalidValue, instead of
+// logging, when WithTransform, WithValueValidator or WithRejectNil rejects
+// value.
+func (c *Cache[T]) AddE(key string, value T) error {
+	key = c.normKey(key)
+	value, err := c.validate(key, value)
+	if err != nil {
+		return err
+	}
+	if c.rejectFrozen("add") {
+		return ErrFrozen
+	}
+	c.set(key, value)
+	return nil
+}
+
+// set is Set without validation.
+func (c *Cache[T]) set(key string, value T) (previous T, replaced bool, evicted bool) {
+	if c.rejectFrozen("add") {
+		return previous, false, false
+	}
+	now := c.clock.Now()
 	c.mu.Lock()
-	defer c.mu.Unlock()
-	c.data[key] = value
+	if old, ok := c.data[key]; ok && !old.expired(now) {
+		previous, replaced = c.output(old.val()), true
+	}
+	c.setLocked(key, c.manualEntry(value, expiryFor(now, c.addTTL())))
+	m := mutation[T]{key: key, value: value, old: previous, oldExists: replaced}
+	muts := append([]mutation[T]{m}, c.evictLocked()...)
+	c.writeBehindStageLocked(muts)
+	h := c.hooks
+	c.mu.Unlock()
+	c.notify(h, muts)
+	return previous, replaced, len(muts) > 1
 }
 
 // Delete removes the item with the given key from the cache.
 func (c *Cache[T]) Delete(key string) {
+	c.remove(key, "")
+}
+
+// DeleteAndGet removes the item with the given key and returns its former
+// value and whether it was present. An expired item is removed but
+// reported as absent.
+func (c *Cache[T]) DeleteAndGet(key string) (T, bool) {
+	e, ok := c.remove(key, "")
+	if !ok || e.expired(c.clock.Now()) {
+		var zero T
+		return zero, false
+	}
+	return c.output(e.val()), true
+}
+
+// remove deletes key, resolving aliases, fires OnDelete hooks and returns
+// the removed entry. An alias resolving to a key outside scope, the prefix
+// of a ScopedCache or "" for the whole cache, removes nothing.
+func (c *Cache[T]) remove(key, scope string) (*entry[T], bool) {
+	if c.rejectFrozen("delete") {
+		return nil, false
+	}
+	key = c.normKey(key)
 	c.mu.Lock()
-	defer c.mu.Unlock()
-	delete(c.data, key)
+	key = c.resolveLocked(key)
+	var e *entry[T]
+	ok := false
+	if strings.HasPrefix(key, scope) {
+		e, ok = c.deleteLocked(key)
+	}
+	var muts []mutation[T]
+	if ok {
+		muts = []mutation[T]{{key: key, deleted: true}}
+		c.writeBehindStageLocked(muts)
+	}
+	h := c.hooks
+	c.mu.Unlock()
+	if ok {
+		c.notify(h, muts)
+	}
+	return e, ok
 }
 
-// Clear empties the entire cache.
+// Clear empties the entire cache, except for pinned items.
 func (c *Cache[T]) Clear() {
+	if c.rejectFrozen("clear") {
+		return
+	}
 	c.mu.Lock()
-	defer c.mu.Unlock()
-	c.data = make(map[string]T)
+	c.replaceLocked(make(map[string]*entry[T]))
+	c.mu.Unlock()
+	c.sizeChanged()
 }
 
 // Get returns the item for a key, and a boolean indicating presence.
+// With sliding expiration enabled, a hit extends the item's expiry.
 func (c *Cache[T]) Get(key string) (T, bool) {
+	return c.get(key, "")
+}
+
+// get implements Get. An alias resolving to a key outside scope, the prefix
+// of a ScopedCache or "" for the whole cache, misses.
+func (c *Cache[T]) get(key, scope string) (T, bool) {
+	key = c.normKey(key)
+	if bf := c.bloom.Load(); bf != nil && !bf.mayContain(key) {
+		c.recordLookup(key, false)
+		var zero T
+		return zero, false
+	}
+	now := c.clock.Now()
+	c.clearExpiredData(now)
 	c.mu.RLock()
 	defer c.mu.RUnlock()
-	val, ok := c.data[key]
-	return val, ok
+	primary := c.resolveLocked(key)
+	e, ok := c.data[primary]
+	if !ok || e.expired(now) || !strings.HasPrefix(primary, scope) {
+		c.recordLookup(key, false)
+		var zero T
+		return zero, false
+	}
+	c.recordLookup(key, true)
+	if c.slidingTTL > 0 && e.expiresAt.Load() != 0 {
+		e.expiresAt.Store(expiryFor(now, c.slidingTTL))
+	}
+	if c.lru != nil {
+		c.lru.used(e)
+	}
+	return c.output(e.val()), true
+}
+
+// GetE is Get for error-propagating callers: an absent or expired key
+// yields an error wrapping ErrNotFound.
+func (c *Cache[T]) GetE(key string) (T, error) {
+	v, ok := c.Get(key)
+	if !ok {
+		return v, fmt.Errorf("%w: %q", ErrNotFound, key)
+	}
+	return v, nil
+}
+
+// MustGet is like GetE but panics if the key is absent. It is meant for
+// tests and initialization code.
+func (c *Cache[T]) MustGet(key string) T {
+	v, err := c.GetE(key)
+	if err != nil {
+		panic(err)
+	}
+	return v
+}
+
+// GetOrDefault returns the item for key, or def if it is absent or
+// expired. Like Get, a miss is counted in the statistics.
+func (c *Cache[T]) GetOrDefault(key string, def T) T {
+	if v, ok := c.Get(key); ok {
+		return v
+	}
+	return def
+}
+
+// GetOrElse returns the item for key, or the result of fn if it is absent
+// or expired. fn is only called on a miss and its result is not stored;
+// see GetOrCompute for that.
+func (c *Cache[T]) GetOrElse(key string, fn func() T) T {
+	if v, ok := c.Get(key); ok {
+		return v
+	}
+	return fn()
 }
 
 // GetAll returns a shallow copy of the entire cached map.
 func (c *Cache[T]) GetAll() map[string]T {
+	return c.GetAllInto(nil)
+}
+
+// GetAllInto clears dst and fills it with the cached items, returning it.
+// Reusing dst across calls avoids allocating a fresh map each time; a nil
+// dst allocates one. Under WithChunkedStorage the map is filled without
+// holding the cache lock.
+func (c *Cache[T]) GetAllInto(dst map[string]T) map[string]T {
+	c.clearExpiredData(c.clock.Now())
+	if c.store != nil {
+		return c.getAllChunked(dst)
+	}
 	c.mu.RLock()
 	defer c.mu.RUnlock()
-	result := make(map[string]T, len(c.data))
-	for k, v := range c.data {
-		result[k] = v
+	now := c.clock.Now()
+	if dst == nil {
+		dst = make(map[string]T, len(c.data))
+	} else {
+		clear(dst)
+	}
+	for k, e := range c.data {
+		if !e.expired(now) {
+			dst[k] = c.output(e.val())
+		}
 	}
-	return result
+	return dst
 }
 
 // Find returns all items satisfying the provided predicate.
 func (c *Cache[T]) Find(predicate func(T) bool) []T {
+	return c.FindInto(nil, predicate)
+}
+
+// FindSeq is Find producing its matches lazily, with their keys, for
+// predicates matching too many items to collect in one slice. Each
+// iteration of the returned sequence, which has the shape of an
+// iter.Seq2[string, T], reads a Snapshot taken as it starts, so changes
+// made while it runs are not visible to it and it holds no lock while
+// yielding; the first write after it starts copies the item map, as for
+// any Snapshot.
+func (c *Cache[T]) FindSeq(predicate func(T) bool) func(yield func(key string, value T) bool) {
+	return func(yield func(string, T) bool) {
+		c.Snapshot().FindSeq(predicate)(yield)
+	}
+}
+
+// FindInto appends the items satisfying predicate to dst and returns the
+// extended slice, like append. Passing dst[:0] lets hot paths reuse one
+// buffer across calls.
+func (c *Cache[T]) FindInto(dst []T, predicate func(T) bool) []T {
+	c.clearExpiredData(c.clock.Now())
 	c.mu.RLock()
 	defer c.mu.RUnlock()
-	var results []T
-	for _, v := range c.data {
-		if predicate(v) {
-			results = append(results, v)
+	c.eachLocked(c.clock.Now(), func(_ string, e *entry[T]) bool {
+		if v := e.val(); predicate(v) {
+			dst = append(dst, c.output(v))
 		}
-	}
-	return results
+		return true
+	})
+	return dst
+}
+
+// FindKeys returns the keys of all items satisfying predicate.
+func (c *Cache[T]) FindKeys(predicate func(T) bool) []string {
+	return c.FindKeysInto(nil, predicate)
+}
+
+// FindKeysInto appends the keys of the items satisfying predicate to dst
+// and returns the extended slice, like append.
+func (c *Cache[T]) FindKeysInto(dst []string, predicate func(T) bool) []string {
+	c.clearExpiredData(c.clock.Now())
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	c.eachLocked(c.clock.Now(), func(k string, e *entry[T]) bool {
+		if predicate(e.val()) {
+			dst = append(dst, k)
+		}
+		return true
+	})
+	return dst
 }
 
 // FindOne returns the first item satisfying predicate, or false if none.
+// Which item is first is unspecified unless WithStableIteration is set.
 func (c *Cache[T]) FindOne(predicate func(T) bool) (T, bool) {
+	c.clearExpiredData(c.clock.Now())
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	var found T
+	var ok bool
+	c.eachLocked(c.clock.Now(), func(_ string, e *entry[T]) bool {
+		if v := e.val(); predicate(v) {
+			found, ok = c.output(v), true
+		}
+		return !ok
+	})
+	return found, ok
+}
+
+// Has reports whether key holds a live item. Unlike Get it counts neither
+// a hit nor a miss and leaves a sliding TTL alone.
+func (c *Cache[T]) Has(key string) bool {
+	key = c.normKey(key)
+	now := c.clock.Now()
+	c.clearExpiredData(now)
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	e, ok := c.data[c.resolveLocked(key)]
+	return ok && !e.expired(now)
+}
+
+// Len returns the number of live items in the cache.
+func (c *Cache[T]) Len() int {
+	now := c.clock.Now()
+	c.clearExpiredData(now)
 	c.mu.RLock()
 	defer c.mu.RUnlock()
-	for _, v := range c.data {
-		if predicate(v) {
-			return v, true
+	n := 0
+	for _, e := range c.data {
+		if !e.expired(now) {
+			n++
 		}
 	}
-	var zero T
-	return zero, false
+	return n
+}
+
+// Generation returns a counter that advances whenever the contents change:
+// by one for each successful Load, SetAll, Add, Delete of a present key and
+// Clear, and by more when a write also evicts, or a chunked reload applies
+// several chunks. Comparing a remembered generation against the current one
+// tells whether anything derived from the contents must be recomputed. Item
+// expiry does not advance it until expired items are swept.
+func (c *Cache[T]) Generation() uint64 {
+	return c.generation.Load()
+}
+
+// Keys returns the keys of all live items, in no particular order unless
+// WithStableIteration is set.
+func (c *Cache[T]) Keys() []string {
+	now := c.clock.Now()
+	c.clearExpiredData(now)
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	if c.stable {
+		return c.sortedKeysLocked(now)
+	}
+	keys := make([]string, 0, len(c.data))
+	for k, e := range c.data {
+		if !e.expired(now) {
+			keys = append(keys, k)
+		}
+	}
+	return keys
+}
+
+// Range calls fn for each live item until fn returns false. It holds the
+// read lock throughout, so fn must not modify the cache; use Snapshot for
+// long-running iteration. The order is unspecified unless
+// WithStableIteration is set.
+func (c *Cache[T]) Range(fn func(key string, value T) bool) {
+	now := c.clock.Now()
+	c.clearExpiredData(now)
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	c.eachLocked(now, func(k string, e *entry[T]) bool {
+		return fn(k, c.output(e.val()))
+	})
+}
+
+// setLocked stores e under key, keeping auxiliary indexes in sync.
+// The caller must hold c.mu for writing.
+func (c *Cache[T]) setLocked(key string, e *entry[T]) {
+	c.ownDataLocked()
+	c.generation.Add(1)
+	e.stamp = c.loadStamp
+	old, exists := c.data[key]
+	delete(c.tombstones, key)
+	e.version = c.nextVersion(old, e)
+	c.journalSetLocked(key, old, e)
+	if c.isPinnedLocked(key) {
+		e.expiresAt.Store(0)
+	}
+	if !exists && c.prefixIndex != nil {
+		c.prefixIndex.insert(key)
+	}
+	for _, ix := range c.allIndexesLocked() {
+		if exists {
+			ix.remove(key, old.val())
+		}
+		ix.add(key, e.val())
+	}
+	c.addBloomLocked(key, e)
+	if exists {
+		c.trackCompressedLocked(old, -1)
+	}
+	c.trackCompressedLocked(e, 1)
+	c.data[key] = e
+	if c.store != nil {
+		c.store.set(key, e, c.data)
+	}
+	if c.lru != nil {
+		c.lru.set(key, old, e)
+	}
+}
+
+// deleteLocked removes key, keeping auxiliary indexes in sync, and returns
+// the removed entry. The caller must hold c.mu for writing.
+func (c *Cache[T]) deleteLocked(key string) (*entry[T], bool) {
+	e, ok := c.data[key]
+	if !ok {
+		return nil, false
+	}
+	c.ownDataLocked()
+	c.generation.Add(1)
+	delete(c.data, key)
+	if c.store != nil {
+		c.store.delete(key, c.data)
+	}
+	if c.journal != nil {
+		c.journal.record(ChangeDelete, key)
+	}
+	if c.prefixIndex != nil {
+		c.prefixIndex.remove(key)
+	}
+	for _, ix := range c.allIndexesLocked() {
+		ix.remove(key, e.val())
+	}
+	c.trackCompressedLocked(e, -1)
+	if c.lru != nil {
+		c.lru.remove(e)
+	}
+	return e, true
+}
+
+// replaceLocked swaps in data as the entire contents, rebuilding auxiliary
+// indexes, and returns the time spent rebuilding secondary indexes.
+// The caller must hold c.mu for writing.
+func (c *Cache[T]) replaceLocked(data map[string]*entry[T]) time.Duration {
+	c.keepPinnedLocked(data)
+	for k, e := range data {
+		if old := c.data[k]; old != e {
+			e.version = c.nextVersion(old, e)
+		}
+	}
+	c.journalReplaceLocked(data)
+	c.data = data
+	c.shared.Store(false)
+	c.generation.Add(1)
+	if c.store != nil {
+		c.store.rebuild(data)
+	}
+	if c.prefixIndex != nil {
+		keys := make([]string, 0, len(data))
+		for k := range data {
+			keys = append(keys, k)
+		}
+		c.prefixIndex.reset(keys)
+	}
+	if c.aliases != nil {
+		c.aliases.rebuild(data)
+	}
+	if c.valueIndex != nil {
+		c.valueIndex.rebuild(data)
+	}
+	c.rebuildBloomLocked(data)
+	if c.lru != nil {
+		c.lru.rebuild(data)
+	}
+	if c.comp != nil {
+		c.compStats = compressionStats{}
+		for _, e := range data {
+			c.trackCompressedLocked(e, 1)
+		}
+	}
+	if len(c.indexes) == 0 {
+		return 0
+	}
+	start := c.clock.Now()
+	for _, ix := range c.indexes {
+		ix.rebuild(data)
+	}
+	return c.clock.Now().Sub(start)
+}
+
+// allIndexesLocked returns the secondary indexes plus the alias and value
+// indexes, all of which are maintained identically on Add and Delete. The
+// caller must hold c.mu.
+func (c *Cache[T]) allIndexesLocked() []*index[T] {
+	if c.aliases == nil && c.valueIndex == nil && len(c.indexes) == 0 {
+		return nil
+	}
+	all := make([]*index[T], 0, len(c.indexes)+2)
+	for _, ix := range c.indexes {
+		all = append(all, ix)
+	}
+	if c.aliases != nil {
+		all = append(all, c.aliases)
+	}
+	if c.valueIndex != nil {
+		all = append(all, c.valueIndex)
+	}
+	return all
+}
+
+// ownDataLocked copies c.data if a Snapshot still references it, so that
+// in-place mutations never leak into snapshots. The caller must hold c.mu
+// for writing.
+func (c *Cache[T]) ownDataLocked() {
+	if c.shared.Load() {
+		c.data = maps.Clone(c.data)
+		c.shared.Store(false)
+	}
+}
+
+// output applies the configured cloner, if any, to a value being returned
+// to a caller.
+func (c *Cache[T]) output(v T) T {
+	if c.cloner != nil {
+		return c.cloner(v)
+	}
+	return v
+}
+
+// input applies the configured cloner to a value being stored by Add when
+// cloning on insert is enabled.
+func (c *Cache[T]) input(v T) T {
+	if c.cloneOnAdd && c.cloner != nil {
+		return c.cloner(v)
+	}
+	return v
 }
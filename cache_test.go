@@ -0,0 +1,136 @@
+package cache_test
+
+import (
+	"maps"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/TheOrchestraX/cache"
+)
+
+func TestGetAllIntoReusesDst(t *testing.T) {
+	c, clk := newTestCache(t, map[string]int{"a": 1, "b": 2})
+	c.AddWithTTL("short", 3, time.Minute)
+	clk.Advance(2 * time.Minute)
+
+	dst := map[string]int{"stale": 9}
+	got := c.GetAllInto(dst)
+	want := map[string]int{"a": 1, "b": 2}
+	if !maps.Equal(got, want) {
+		t.Fatalf("GetAllInto = %v, want %v", got, want)
+	}
+	got["marker"] = 0
+	if _, ok := dst["marker"]; !ok {
+		t.Fatal("GetAllInto did not return dst")
+	}
+	if got := c.GetAllInto(nil); !maps.Equal(got, want) {
+		t.Fatalf("GetAllInto(nil) = %v, want %v", got, want)
+	}
+}
+
+func TestFindIntoAppends(t *testing.T) {
+	c, _ := newTestCache(t, map[string]int{"a": 1, "b": 2, "c": 3, "d": 4})
+	even := func(v int) bool { return v%2 == 0 }
+
+	got := c.FindInto([]int{-1}, even)
+	slices.Sort(got)
+	if want := []int{-1, 2, 4}; !slices.Equal(got, want) {
+		t.Fatalf("FindInto = %v, want %v", got, want)
+	}
+	buf := make([]int, 0, 8)
+	got = c.FindInto(buf, even)
+	if len(got) != 2 || &got[:1][0] != &buf[:1][0] {
+		t.Fatalf("FindInto into spare capacity = %v, want the same backing array", got)
+	}
+	if got := c.FindInto(nil, func(int) bool { return false }); got != nil {
+		t.Fatalf("FindInto(nil) with no matches = %v, want nil", got)
+	}
+
+	keys := c.FindKeysInto([]string{"x"}, even)
+	slices.Sort(keys)
+	if want := []string{"b", "d", "x"}; !slices.Equal(keys, want) {
+		t.Fatalf("FindKeysInto = %v, want %v", keys, want)
+	}
+}
+
+func TestFindSeq(t *testing.T) {
+	c, _ := newTestCache(t, map[string]int{"a": 1, "b": 2, "c": 3, "d": 4})
+	even := func(v int) bool { return v%2 == 0 }
+
+	got := make(map[string]int)
+	c.FindSeq(even)(func(k string, v int) bool {
+		got[k] = v
+		// Writes while iterating neither deadlock nor show up.
+		c.Add("new"+k, 10)
+		c.Delete("d")
+		return true
+	})
+	if want := map[string]int{"b": 2, "d": 4}; !maps.Equal(got, want) {
+		t.Fatalf("FindSeq yielded %v, want %v", got, want)
+	}
+	mustMiss(t, c, "d")
+
+	n := 0
+	c.FindSeq(func(int) bool { return true })(func(string, int) bool {
+		n++
+		return false
+	})
+	if n != 1 {
+		t.Fatalf("FindSeq yielded %d items after the consumer stopped, want 1", n)
+	}
+}
+
+func TestSetReportsFreshAndReplaced(t *testing.T) {
+	c, clk := newTestCache(t, map[string]int{"a": 1})
+
+	prev, replaced, evicted := c.Set("b", 2)
+	if prev != 0 || replaced || evicted {
+		t.Fatalf("Set(fresh) = %d, %v, %v; want 0, false, false", prev, replaced, evicted)
+	}
+	prev, replaced, evicted = c.Set("a", 10)
+	if prev != 1 || !replaced || evicted {
+		t.Fatalf("Set(existing) = %d, %v, %v; want 1, true, false", prev, replaced, evicted)
+	}
+	if v := mustGet(t, c, "a"); v != 10 {
+		t.Fatalf("Get(a) = %d, want 10", v)
+	}
+
+	// An expired item is not reported as replaced.
+	c.AddWithTTL("short", 3, time.Minute)
+	clk.Advance(2 * time.Minute)
+	if prev, replaced, _ := c.Set("short", 4); prev != 0 || replaced {
+		t.Fatalf("Set(expired) = %d, %v; want 0, false", prev, replaced)
+	}
+}
+
+func TestSetReportsEvictionInLRUOrder(t *testing.T) {
+	c, _ := newTestCache(t, map[string]int{}, cache.WithMaxEntries[int](3))
+	var evictedKeys []string
+	c.OnEvict(func(key string, _ int) { evictedKeys = append(evictedKeys, key) })
+	for i, k := range []string{"a", "b", "c"} {
+		if _, _, evicted := c.Set(k, i); evicted {
+			t.Fatalf("Set(%q) evicted below the limit", k)
+		}
+	}
+	mustGet(t, c, "a") // b is now the least recently used
+
+	if _, _, evicted := c.Set("d", 3); !evicted {
+		t.Fatal("Set(d) over the limit reported no eviction")
+	}
+	mustGet(t, c, "c") // a is now the least recently used
+	if _, _, evicted := c.Set("e", 4); !evicted {
+		t.Fatal("Set(e) over the limit reported no eviction")
+	}
+	if _, _, evicted := c.Set("e", 5); evicted {
+		t.Fatal("replacing an item at the limit reported an eviction")
+	}
+	if want := []string{"b", "a"}; !slices.Equal(evictedKeys, want) {
+		t.Fatalf("evicted %v, want %v", evictedKeys, want)
+	}
+	keys := c.Keys()
+	slices.Sort(keys)
+	if want := []string{"c", "d", "e"}; !slices.Equal(keys, want) {
+		t.Fatalf("Keys = %v, want %v", keys, want)
+	}
+}
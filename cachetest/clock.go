@@ -0,0 +1,174 @@
+// Package cachetest provides helpers for testing code built on the cache
+// package, most notably a manually driven clock.
+package cachetest
+
+import (
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/TheOrchestraX/cache"
+)
+
+// FakeClock is a cache.Clock whose time only moves when Advance or Set is
+// called. Tickers and timers created from it fire synchronously during
+// those calls, in chronological order.
+type FakeClock struct {
+	mu      sync.Mutex
+	now     time.Time
+	waiters []*fakeWaiter // the active waiters only
+}
+
+var _ cache.Clock = (*FakeClock)(nil)
+
+// NewFakeClock returns a FakeClock reading start.
+func NewFakeClock(start time.Time) *FakeClock {
+	return &FakeClock{now: start}
+}
+
+// Now returns the clock's current time.
+func (f *FakeClock) Now() time.Time {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.now
+}
+
+// Advance moves the clock forward by d, firing every ticker and timer that
+// falls due along the way.
+func (f *FakeClock) Advance(d time.Duration) {
+	f.Set(f.Now().Add(d))
+}
+
+// Set moves the clock to t, firing every ticker and timer due at or before
+// t. Moving the clock backwards fires nothing.
+func (f *FakeClock) Set(t time.Time) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for {
+		w := f.nextDueLocked(t)
+		if w == nil {
+			break
+		}
+		f.now = w.next
+		select {
+		case w.ch <- w.next:
+		default: // drop ticks nobody is receiving, like time.Ticker
+		}
+		if w.period > 0 {
+			w.next = w.next.Add(w.period)
+		} else {
+			f.removeLocked(w)
+		}
+	}
+	if t.After(f.now) {
+		f.now = t
+	}
+}
+
+// Waiters returns the number of active tickers and timers, which lets a
+// test wait until a background goroutine has armed its timer.
+func (f *FakeClock) Waiters() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return len(f.waiters)
+}
+
+// NewTicker returns a ticker firing every d of fake time.
+func (f *FakeClock) NewTicker(d time.Duration) cache.Ticker {
+	if d <= 0 {
+		panic("cachetest: non-positive interval for NewTicker")
+	}
+	return &fakeTicker{f.add(d, d)}
+}
+
+// NewTimer returns a timer firing once after d of fake time.
+func (f *FakeClock) NewTimer(d time.Duration) cache.Timer {
+	return &fakeTimer{f.add(d, 0)}
+}
+
+// add registers a waiter due after d, repeating every period if non-zero.
+func (f *FakeClock) add(d, period time.Duration) *fakeWaiter {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	w := &fakeWaiter{
+		clock:  f,
+		ch:     make(chan time.Time, 1),
+		next:   f.now.Add(d),
+		period: period,
+		active: true,
+	}
+	f.waiters = append(f.waiters, w)
+	return w
+}
+
+// removeLocked deactivates w and drops it from the waiters, so stopped
+// and fired timers do not accumulate.
+func (f *FakeClock) removeLocked(w *fakeWaiter) {
+	w.active = false
+	if i := slices.Index(f.waiters, w); i >= 0 {
+		f.waiters = slices.Delete(f.waiters, i, i+1)
+	}
+}
+
+// nextDueLocked returns the earliest active waiter due at or before t.
+func (f *FakeClock) nextDueLocked(t time.Time) *fakeWaiter {
+	var due *fakeWaiter
+	for _, w := range f.waiters {
+		if w.next.After(t) {
+			continue
+		}
+		if due == nil || w.next.Before(due.next) {
+			due = w
+		}
+	}
+	return due
+}
+
+// fakeWaiter is the shared state behind fake tickers and timers.
+type fakeWaiter struct {
+	clock  *FakeClock
+	ch     chan time.Time
+	next   time.Time
+	period time.Duration
+	active bool
+}
+
+// stop deactivates the waiter, reporting whether it was active.
+func (w *fakeWaiter) stop() bool {
+	w.clock.mu.Lock()
+	defer w.clock.mu.Unlock()
+	was := w.active
+	w.clock.removeLocked(w)
+	return was
+}
+
+// reset re-arms the waiter to fire d from now, repeating every period if
+// non-zero, and reports whether it was active.
+func (w *fakeWaiter) reset(d, period time.Duration) bool {
+	w.clock.mu.Lock()
+	defer w.clock.mu.Unlock()
+	was := w.active
+	w.next = w.clock.now.Add(d)
+	w.period = period
+	if !was {
+		w.active = true
+		w.clock.waiters = append(w.clock.waiters, w)
+	}
+	return was
+}
+
+type fakeTicker struct{ *fakeWaiter }
+
+func (t *fakeTicker) C() <-chan time.Time { return t.ch }
+
+func (t *fakeTicker) Stop() { t.stop() }
+
+func (t *fakeTicker) Reset(d time.Duration) { t.reset(d, d) }
+
+type fakeTimer struct{ *fakeWaiter }
+
+func (t *fakeTimer) C() <-chan time.Time { return t.ch }
+
+func (t *fakeTimer) Stop() bool { return t.stop() }
+
+func (t *fakeTimer) Reset(d time.Duration) bool { return t.reset(d, 0) }
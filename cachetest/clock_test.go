@@ -0,0 +1,68 @@
+package cachetest_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/TheOrchestraX/cache/cachetest"
+)
+
+func TestFakeClockTimerFires(t *testing.T) {
+	clk := cachetest.NewFakeClock(time.Unix(0, 0))
+	tm := clk.NewTimer(time.Second)
+	clk.Advance(999 * time.Millisecond)
+	select {
+	case <-tm.C():
+		t.Fatal("timer fired early")
+	default:
+	}
+	clk.Advance(time.Millisecond)
+	select {
+	case <-tm.C():
+	default:
+		t.Fatal("timer did not fire")
+	}
+}
+
+func TestFakeClockDropsStoppedWaiters(t *testing.T) {
+	clk := cachetest.NewFakeClock(time.Unix(0, 0))
+	for i := 0; i < 100; i++ {
+		clk.NewTimer(time.Hour).Stop()
+		clk.NewTicker(time.Hour).Stop()
+	}
+	fired := clk.NewTimer(time.Second)
+	clk.Advance(time.Second)
+	if n := clk.Waiters(); n != 0 {
+		t.Fatalf("Waiters() = %d after stopping and firing all, want 0", n)
+	}
+
+	// A fired or stopped timer can be re-armed.
+	fired.Reset(time.Second)
+	if n := clk.Waiters(); n != 1 {
+		t.Fatalf("Waiters() = %d after Reset, want 1", n)
+	}
+	<-fired.C()
+	clk.Advance(time.Second)
+	select {
+	case <-fired.C():
+	default:
+		t.Fatal("re-armed timer did not fire")
+	}
+}
+
+func TestFakeClockTicker(t *testing.T) {
+	clk := cachetest.NewFakeClock(time.Unix(0, 0))
+	tk := clk.NewTicker(time.Second)
+	defer tk.Stop()
+	for i := 0; i < 3; i++ {
+		clk.Advance(time.Second)
+		select {
+		case <-tk.C():
+		default:
+			t.Fatalf("tick %d missing", i)
+		}
+	}
+	if n := clk.Waiters(); n != 1 {
+		t.Fatalf("Waiters() = %d, want 1", n)
+	}
+}
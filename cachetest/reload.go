@@ -0,0 +1,13 @@
+package cachetest
+
+// Triggerer is implemented by *cache.Cache[T] for any T.
+type Triggerer interface {
+	TriggerReload() <-chan struct{}
+}
+
+// TriggerReload makes the cache's auto-reload goroutine run a load and
+// blocks until the swap and all hooks have completed, so assertions made
+// immediately afterwards are deterministic. It is intended for tests only.
+func TriggerReload(c Triggerer) {
+	<-c.TriggerReload()
+}
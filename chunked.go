@@ -0,0 +1,137 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// WithChunkedReload trades the atomicity of reloads for bounded memory.
+// Instead of building a complete replacement map and swapping it in, Load
+// merges the loader's entries into the live map chunkSize at a time, then
+// sweeps out every key the load did not produce. It is most useful with
+// NewStreamingCache, where the full dataset is never held in memory twice.
+//
+// While a chunked reload runs, readers may observe a mix of old and new
+// entries. If the loader fails part-way, the chunks already merged stay in
+// place and no sweep happens, so stale keys remain until the next
+// successful load; the same goes for a reload cancelled by StopAutoReload.
+// Items added with Add during a reload survive its sweep.
+// A chunkSize <= 0 keeps the default atomic swap.
+func WithChunkedReload[T any](chunkSize int) Option[T] {
+	return func(c *Cache[T]) {
+		c.chunkSize = chunkSize
+	}
+}
+
+// loadChunked performs a Load under WithChunkedReload. Each load takes a
+// fresh stamp that setLocked records on every entry it stores; once the
+// loader finishes, entries still carrying an older stamp were not part of
+// this load and are deleted.
+func (c *Cache[T]) loadChunked(ctx context.Context) error {
+	c.mu.Lock()
+	c.loadStamp++
+	stamp := c.loadStamp
+	exp := expiryFor(c.clock.Now(), c.loadTTL())
+	c.mu.Unlock()
+
+	type pending struct {
+		key string
+		e   *entry[T]
+	}
+	batch := make([]pending, 0, c.chunkSize)
+	flush := func() error {
+		c.mu.Lock()
+		if err := ctx.Err(); err != nil {
+			c.mu.Unlock()
+			return err
+		}
+		if c.frozen.Load() {
+			c.mu.Unlock()
+			c.rejectFrozen("load")
+			return ErrFrozen
+		}
+		now := c.clock.Now()
+		var changes []mutation[T]
+		for _, p := range batch {
+			if !c.tombstonedLocked(p.key, now.UnixNano()) {
+				changes = c.loadChangeLocked(changes, p.key, c.data[p.key], p.e, now)
+				c.setLocked(p.key, p.e)
+			}
+		}
+		evicted := c.evictLocked()
+		h := c.hooks
+		c.mu.Unlock()
+		c.notifyChanges(h, changes)
+		c.notify(h, evicted)
+		clear(batch)
+		batch = batch[:0]
+		return nil
+	}
+	loaded := 0
+	var drops invalidDrops
+	err := c.callStream(ctx, func(key string, value T) error {
+		value, keep, err := c.checkLoaded(key, value, &drops)
+		if !keep {
+			return err
+		}
+		batch = append(batch, pending{key, c.makeEntry(value, exp)})
+		loaded++
+		if len(batch) == c.chunkSize {
+			return flush()
+		}
+		return nil
+	})
+	if err == nil {
+		err = flush()
+	}
+	c.logDrops(drops)
+	if ctx.Err() != nil {
+		return c.loadCancelled(ctx)
+	}
+	if errors.Is(err, ErrFrozen) {
+		return c.loadAbandoned(err)
+	}
+	if err != nil {
+		return c.loadFailed(err)
+	}
+
+	c.mu.Lock()
+	if ctx.Err() != nil {
+		c.mu.Unlock()
+		return c.loadCancelled(ctx)
+	}
+	if c.frozen.Load() {
+		c.mu.Unlock()
+		c.rejectFrozen("load")
+		return c.loadAbandoned(ErrFrozen)
+	}
+	var stale, kept []string
+	for k, e := range c.data {
+		switch {
+		case e.stamp == stamp:
+		case c.isPinnedLocked(k):
+			kept = append(kept, k)
+		default:
+			stale = append(stale, k)
+		}
+	}
+	for _, k := range stale {
+		c.deleteLocked(k)
+	}
+	c.loadSucceededLocked()
+	c.recordHistoryLocked()
+	h := c.hooks
+	c.mu.Unlock()
+	c.gauge("consecutive_failures", 0)
+	c.sizeChanged()
+	for _, fn := range h.onReload {
+		fn(loaded)
+	}
+	if len(kept) > 0 {
+		c.logf(LogErrorsOnly, "%s reload dropped pinned keys %q; keeping their old values", c.label(), kept)
+	}
+
+	c.logf(LogInfo, "[%s] %s reloaded in chunks (%d items, %d stale removed)", c.clock.Now().Format(time.RFC3339), c.label(), loaded, len(stale))
+	return nil
+}
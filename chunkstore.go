@@ -0,0 +1,144 @@
+package cache
+
+import (
+	"hash/maphash"
+	"maps"
+	"slices"
+	"sync/atomic"
+)
+
+// WithChunkedStorage additionally keeps the items in copy-on-write chunks
+// of about chunkSize items each, so that GetAll and GetAllInto hold the
+// read lock only long enough to reference the chunks and build their
+// result without it. Writers are then never queued behind a large GetAll;
+// instead, the first write to a chunk after a GetAll copies that chunk, so
+// the extra work is bounded by the write rate and chunkSize rather than by
+// the size of the cache. It costs a second set of item pointers, and loads
+// spend a little longer rebuilding the chunks. It is unrelated to
+// WithChunkedReload. A chunkSize <= 0 disables it.
+func WithChunkedStorage[T any](chunkSize int) Option[T] {
+	return func(c *Cache[T]) {
+		if chunkSize <= 0 {
+			c.store = nil
+			return
+		}
+		c.store = &chunkStore[T]{size: chunkSize, seed: maphash.MakeSeed()}
+		c.store.rebuild(c.data)
+	}
+}
+
+// chunkedStorageSize returns the WithChunkedStorage chunk size, or 0.
+func (c *Cache[T]) chunkedStorageSize() int {
+	if c.store == nil {
+		return 0
+	}
+	return c.store.size
+}
+
+// chunkStore is the WithChunkedStorage mirror of c.data: the same entries,
+// spread over chunks by key hash. A chunk handed to a reader is marked
+// shared and never modified again; the next write to it replaces it with a
+// copy. The chunk count is reset on every rebuild, and the chunks are
+// rebuilt when they grow to twice or shrink to a quarter of their target
+// size on average. All fields are guarded by Cache.mu.
+type chunkStore[T any] struct {
+	size   int // target items per chunk
+	seed   maphash.Seed
+	chunks []*storeChunk[T]
+	n      int // items across all chunks
+}
+
+// storeChunk is one chunk of a chunkStore.
+type storeChunk[T any] struct {
+	items  map[string]*entry[T]
+	shared atomic.Bool // referenced by a reader; copy before writing
+}
+
+// rebuild replaces the chunks with a fresh set holding data.
+func (s *chunkStore[T]) rebuild(data map[string]*entry[T]) {
+	s.chunks = make([]*storeChunk[T], max(1, (len(data)+s.size-1)/s.size))
+	for i := range s.chunks {
+		s.chunks[i] = &storeChunk[T]{items: make(map[string]*entry[T], s.size)}
+	}
+	for k, e := range data {
+		s.chunks[s.index(k)].items[k] = e
+	}
+	s.n = len(data)
+}
+
+// index returns the position of the chunk holding key.
+func (s *chunkStore[T]) index(key string) int {
+	return int(maphash.String(s.seed, key) % uint64(len(s.chunks)))
+}
+
+// writable returns the chunk holding key, first replacing it with a copy
+// if a reader holds it.
+func (s *chunkStore[T]) writable(key string) *storeChunk[T] {
+	i := s.index(key)
+	ch := s.chunks[i]
+	if ch.shared.Load() {
+		ch = &storeChunk[T]{items: maps.Clone(ch.items)}
+		s.chunks[i] = ch
+	}
+	return ch
+}
+
+// set stores e under key. data is c.data, already updated, in case the
+// chunks must be rebuilt.
+func (s *chunkStore[T]) set(key string, e *entry[T], data map[string]*entry[T]) {
+	ch := s.writable(key)
+	if _, ok := ch.items[key]; !ok {
+		s.n++
+	}
+	ch.items[key] = e
+	if s.n > 2*s.size*len(s.chunks) {
+		s.rebuild(data)
+	}
+}
+
+// delete removes key. data is c.data, already updated, in case the chunks
+// must be rebuilt.
+func (s *chunkStore[T]) delete(key string, data map[string]*entry[T]) {
+	ch := s.writable(key)
+	if _, ok := ch.items[key]; !ok {
+		return
+	}
+	delete(ch.items, key)
+	s.n--
+	if len(s.chunks) > 1 && s.n < s.size*len(s.chunks)/4 {
+		s.rebuild(data)
+	}
+}
+
+// share marks every chunk shared and returns them, so that they can be
+// read once the lock is released.
+func (s *chunkStore[T]) share() []*storeChunk[T] {
+	for _, ch := range s.chunks {
+		if !ch.shared.Load() {
+			ch.shared.Store(true)
+		}
+	}
+	return slices.Clone(s.chunks)
+}
+
+// getAllChunked implements GetAllInto under WithChunkedStorage.
+func (c *Cache[T]) getAllChunked(dst map[string]T) map[string]T {
+	c.mu.RLock()
+	now := c.clock.Now()
+	chunks := c.store.share()
+	n := c.store.n
+	c.mu.RUnlock()
+	if dst == nil {
+		dst = make(map[string]T, n)
+	} else {
+		clear(dst)
+	}
+	for _, ch := range chunks {
+		for k, e := range ch.items {
+			if !e.expired(now) {
+				dst[k] = c.output(e.val())
+			}
+		}
+	}
+	return dst
+}
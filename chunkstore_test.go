@@ -0,0 +1,105 @@
+package cache_test
+
+import (
+	"fmt"
+	"maps"
+	"math/rand/v2"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/TheOrchestraX/cache"
+)
+
+// rangeAll collects the items of c through Range, which never uses the
+// chunks.
+func rangeAll[T any](c *cache.Cache[T]) map[string]T {
+	all := make(map[string]T)
+	c.Range(func(k string, v T) bool {
+		all[k] = v
+		return true
+	})
+	return all
+}
+
+func TestChunkedStorageMatchesMap(t *testing.T) {
+	c, clk := newTestCache(t, map[string]int{"seed": 0}, cache.WithChunkedStorage[int](4))
+	if got := c.Config().ChunkedStorage; got != 4 {
+		t.Fatalf("Config().ChunkedStorage = %d, want 4", got)
+	}
+	r := rand.New(rand.NewPCG(1, 2))
+	reused := make(map[string]int)
+	for i := range 5000 {
+		key := fmt.Sprintf("k%d", r.IntN(200))
+		switch op := r.IntN(100); {
+		case op < 55:
+			c.Add(key, i)
+		case op < 60:
+			c.AddWithTTL(key, i, time.Duration(1+r.IntN(5))*time.Second)
+		case op < 90:
+			c.Delete(key)
+		case op < 93:
+			clk.Advance(time.Second)
+		case op < 95:
+			c.DeleteExpired()
+		case op < 97:
+			c.GetAll() // shares every chunk, so later writes copy them
+		case op < 98:
+			c.Clear()
+		default:
+			if err := c.Load(); err != nil {
+				t.Fatal(err)
+			}
+		}
+		want := rangeAll(c)
+		if got := c.GetAll(); !maps.Equal(got, want) {
+			t.Fatalf("op %d: GetAll = %v, want %v", i, got, want)
+		}
+		if got := c.GetAllInto(reused); !maps.Equal(got, want) {
+			t.Fatalf("op %d: GetAllInto = %v, want %v", i, got, want)
+		}
+	}
+}
+
+func TestChunkedStorageConcurrentGetAll(t *testing.T) {
+	c, _ := newTestCache(t, map[string]int{}, cache.WithChunkedStorage[int](16))
+	keys := make([]string, 500)
+	for i := range keys {
+		keys[i] = fmt.Sprintf("k%d", i)
+	}
+	var wg sync.WaitGroup
+	stop := make(chan struct{})
+	for w := range 4 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; ; i++ {
+				select {
+				case <-stop:
+					return
+				default:
+				}
+				k := keys[(i*7+w)%len(keys)]
+				if i%3 == 0 {
+					c.Delete(k)
+				} else {
+					c.Add(k, i)
+				}
+			}
+		}()
+	}
+	for range 200 {
+		all := c.GetAll()
+		for k := range all {
+			if !slices.Contains(keys, k) {
+				t.Errorf("GetAll returned unknown key %q", k)
+			}
+		}
+	}
+	close(stop)
+	wg.Wait()
+	if got, want := c.GetAll(), rangeAll(c); !maps.Equal(got, want) {
+		t.Fatalf("GetAll after writers = %d items, want %d", len(got), len(want))
+	}
+}
@@ -0,0 +1,170 @@
+package cache
+
+import (
+	"encoding/json"
+	"reflect"
+	"sync/atomic"
+)
+
+// defaultCompressionThreshold is the serialized size, in bytes, above which
+// values are compressed when no threshold is configured.
+const defaultCompressionThreshold = 1024
+
+// Codec compresses and decompresses serialized values. See the gzipcodec
+// package for a ready-made implementation.
+type Codec interface {
+	Encode(src []byte) []byte
+	Decode(src []byte) ([]byte, error)
+}
+
+// compression holds the settings for storing large values compressed.
+type compression[T any] struct {
+	codec     Codec
+	threshold int
+	marshal   func(T) ([]byte, error)
+	unmarshal func([]byte) (T, error)
+	onError   func(err error) // logs a failed decode
+}
+
+// packedValue is a value stored in serialized, compressed form.
+type packedValue[T any] struct {
+	data    []byte
+	logical int // serialized size before compression
+	comp    *compression[T]
+}
+
+// unpack decodes the stored value. makeEntry checked that these very bytes
+// decode back to the value, so failure means a codec that is not
+// deterministic; it is logged and yields the zero value.
+func (p *packedValue[T]) unpack() T {
+	v, err := p.comp.decode(p.data)
+	if err != nil {
+		p.comp.onError(err)
+	}
+	return v
+}
+
+// decode reverses encoding and serializing a value.
+func (comp *compression[T]) decode(data []byte) (T, error) {
+	raw, err := comp.codec.Decode(data)
+	if err != nil {
+		var zero T
+		return zero, err
+	}
+	return comp.unmarshal(raw)
+}
+
+// compressionStats tracks the footprint of compressed values.
+type compressionStats struct {
+	items   atomic.Int64
+	logical atomic.Int64
+	stored  atomic.Int64
+}
+
+// WithCompression stores values whose serialized form exceeds the
+// compression threshold (1 KiB unless set by WithCompressionThreshold)
+// compressed with codec, decompressing them on every read. Values are
+// serialized with encoding/json unless WithSerializer is given; []byte and
+// string values are stored as-is before compression. Each value is decoded
+// once as it is stored and kept uncompressed unless it comes back equal, as
+// by reflect.DeepEqual, so values that do not survive the serializer, such
+// as structs with unexported fields under encoding/json, are never
+// corrupted; they merely miss out on compression.
+func WithCompression[T any](codec Codec) Option[T] {
+	return func(c *Cache[T]) {
+		c.compressionConfig().codec = codec
+	}
+}
+
+// WithCompressionThreshold sets the serialized size in bytes above which
+// values are compressed.
+func WithCompressionThreshold[T any](bytes int) Option[T] {
+	return func(c *Cache[T]) {
+		c.compressionConfig().threshold = bytes
+	}
+}
+
+// WithSerializer sets how values are converted to bytes for compression.
+func WithSerializer[T any](marshal func(T) ([]byte, error), unmarshal func([]byte) (T, error)) Option[T] {
+	return func(c *Cache[T]) {
+		cfg := c.compressionConfig()
+		cfg.marshal, cfg.unmarshal = marshal, unmarshal
+	}
+}
+
+// compressionConfig returns the compression settings, creating them with
+// defaults on first use.
+func (c *Cache[T]) compressionConfig() *compression[T] {
+	if c.comp == nil {
+		c.comp = &compression[T]{
+			threshold: defaultCompressionThreshold,
+			marshal:   defaultMarshal[T],
+			unmarshal: defaultUnmarshal[T],
+			onError: func(err error) {
+				c.logf(LogErrorsOnly, "%s decoding compressed value: %v", c.label(), err)
+			},
+		}
+	}
+	return c.comp
+}
+
+// makeEntry builds the entry for value, compressing it when compression is
+// enabled and its serialized form exceeds the threshold. Values that fail
+// to serialize, or do not decode back to an equal value, are stored
+// uncompressed.
+func (c *Cache[T]) makeEntry(value T, expiresAt int64) *entry[T] {
+	if c.comp == nil || c.comp.codec == nil {
+		return newEntry(value, expiresAt)
+	}
+	raw, err := c.comp.marshal(value)
+	if err != nil || len(raw) <= c.comp.threshold {
+		return newEntry(value, expiresAt)
+	}
+	data := c.comp.codec.Encode(raw)
+	if back, err := c.comp.decode(data); err != nil || !reflect.DeepEqual(back, value) {
+		return newEntry(value, expiresAt)
+	}
+	e := &entry[T]{packed: &packedValue[T]{
+		data:    data,
+		logical: len(raw),
+		comp:    c.comp,
+	}}
+	e.expiresAt.Store(expiresAt)
+	return e
+}
+
+// trackCompressedLocked adjusts the compression statistics when e enters
+// (sign 1) or leaves (sign -1) the cache. The caller must hold c.mu.
+func (c *Cache[T]) trackCompressedLocked(e *entry[T], sign int64) {
+	if e.packed == nil {
+		return
+	}
+	c.compStats.items.Add(sign)
+	c.compStats.logical.Add(sign * int64(e.packed.logical))
+	c.compStats.stored.Add(sign * int64(len(e.packed.data)))
+}
+
+// defaultMarshal serializes []byte and string values verbatim and anything
+// else as JSON.
+func defaultMarshal[T any](v T) ([]byte, error) {
+	switch x := any(v).(type) {
+	case []byte:
+		return x, nil
+	case string:
+		return []byte(x), nil
+	}
+	return json.Marshal(v)
+}
+
+// defaultUnmarshal reverses defaultMarshal.
+func defaultUnmarshal[T any](b []byte) (T, error) {
+	var v T
+	switch any(v).(type) {
+	case []byte:
+		return any(b).(T), nil
+	case string:
+		return any(string(b)).(T), nil
+	}
+	err := json.Unmarshal(b, &v)
+	return v, err
+}
@@ -0,0 +1,120 @@
+package cache_test
+
+import (
+	"compress/gzip"
+	"errors"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/TheOrchestraX/cache"
+	"github.com/TheOrchestraX/cache/gzipcodec"
+)
+
+type doc struct {
+	Title string
+	Body  string
+}
+
+// private does not survive encoding/json: its fields are unexported.
+type private struct {
+	body string
+}
+
+// countingCodec wraps gzip, counting decodes and failing them on demand.
+type countingCodec struct {
+	gz      gzipcodec.Codec
+	decodes atomic.Int32
+	broken  atomic.Bool
+}
+
+func newCountingCodec(t *testing.T) *countingCodec {
+	gz, err := gzipcodec.New(gzip.BestSpeed)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &countingCodec{gz: gz}
+}
+
+func (c *countingCodec) Encode(src []byte) []byte { return c.gz.Encode(src) }
+
+func (c *countingCodec) Decode(src []byte) ([]byte, error) {
+	c.decodes.Add(1)
+	if c.broken.Load() {
+		return nil, errors.New("corrupt")
+	}
+	return c.gz.Decode(src)
+}
+
+var bigBody = strings.Repeat("lorem ipsum ", 500)
+
+func TestCompressionRoundTrip(t *testing.T) {
+	codec := newCountingCodec(t)
+	c, _ := newTestCache(t, map[string]doc{
+		"big":   {Title: "big", Body: bigBody},
+		"small": {Title: "small"},
+	}, cache.WithCompression[doc](codec))
+	s := c.Stats()
+	if s.CompressedItems != 1 || s.CompressedBytes >= s.LogicalBytes {
+		t.Fatalf("Stats = %d items, %d of %d bytes; want the big item compressed",
+			s.CompressedItems, s.CompressedBytes, s.LogicalBytes)
+	}
+	if v := mustGet(t, c, "big"); v.Body != bigBody {
+		t.Fatal("big item did not round-trip")
+	}
+	if v := mustGet(t, c, "small"); v.Title != "small" {
+		t.Fatalf("Get(small) = %+v", v)
+	}
+
+	// Scans decode each compressed value once.
+	before := codec.decodes.Load()
+	if got := c.Find(func(d doc) bool { return d.Title == "big" }); len(got) != 1 {
+		t.Fatalf("Find returned %d items, want 1", len(got))
+	}
+	if _, ok := c.FindOne(func(d doc) bool { return d.Title == "big" }); !ok {
+		t.Fatal("FindOne found nothing")
+	}
+	if n := codec.decodes.Load() - before; n != 2 {
+		t.Fatalf("Find and FindOne decoded %d times, want once each", n)
+	}
+}
+
+func TestCompressionKeepsLossyValuesUncompressed(t *testing.T) {
+	c, _ := newTestCache(t, map[string]private{"p": {body: bigBody}},
+		cache.WithCompression[private](newCountingCodec(t)))
+	if n := c.Stats().CompressedItems; n != 0 {
+		t.Fatalf("CompressedItems = %d, want 0 for a value JSON cannot round-trip", n)
+	}
+	if v := mustGet(t, c, "p"); v.body != bigBody {
+		t.Fatal("value with unexported fields was corrupted")
+	}
+}
+
+func TestCompressionWithSerializer(t *testing.T) {
+	c, _ := newTestCache(t, map[string]private{"p": {body: bigBody}},
+		cache.WithCompression[private](newCountingCodec(t)),
+		cache.WithSerializer(
+			func(p private) ([]byte, error) { return []byte(p.body), nil },
+			func(b []byte) (private, error) { return private{body: string(b)}, nil }))
+	if n := c.Stats().CompressedItems; n != 1 {
+		t.Fatalf("CompressedItems = %d, want 1", n)
+	}
+	if v := mustGet(t, c, "p"); v.body != bigBody {
+		t.Fatal("value did not round-trip through the serializer")
+	}
+}
+
+func TestCompressionDecodeFailureDoesNotPanic(t *testing.T) {
+	codec := newCountingCodec(t)
+	c, _ := newTestCache(t, map[string]string{"big": bigBody},
+		cache.WithCompression[string](codec))
+	codec.broken.Store(true)
+	if v, ok := c.Get("big"); !ok || v != "" {
+		t.Fatalf("Get = %q, %v; want the zero value for an undecodable item", v, ok)
+	}
+	// A value that fails to decode as it is stored is kept uncompressed.
+	c.Add("later", bigBody)
+	if v := mustGet(t, c, "later"); v != bigBody {
+		t.Fatal("value stored while the codec was failing did not round-trip")
+	}
+}
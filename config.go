@@ -0,0 +1,148 @@
+package cache
+
+import (
+	"slices"
+	"time"
+)
+
+// Config is a copy of a cache's effective settings, as resolved from its
+// options and any later runtime changes such as SetInterval or
+// SetDefaultTTL. Modifying it has no effect on the cache.
+type Config struct {
+	Name     string
+	Interval time.Duration
+	LogLevel LogLevel
+
+	DefaultTTL       time.Duration
+	DefaultTTLOnLoad bool
+	SlidingTTL       time.Duration
+
+	// Scheduled reports whether reloads follow a WithSchedule schedule
+	// rather than Interval.
+	Scheduled bool
+	// StartDelay delays the first automatic reload; with RandomStartDelay
+	// it is the upper bound of a random delay.
+	StartDelay       time.Duration
+	RandomStartDelay bool
+	// LoadOnStart reports WithLoadOnStart.
+	LoadOnStart bool
+
+	// ChunkSize is the WithChunkedReload chunk size; 0 means reloads swap
+	// the whole map atomically.
+	ChunkSize int
+	// ChunkedStorage is the WithChunkedStorage chunk size, 0 without it.
+	ChunkedStorage    int
+	MinReloadInterval time.Duration
+	CoalesceReloads   bool
+	NilResultError    bool
+	// Partitions is the number of WithPartitionedLoader partitions, 0
+	// without one, and PartialFailurePolicy their policy.
+	Partitions           int
+	PartialFailurePolicy PartialFailurePolicy
+	// MaxEntries and MaxWeight are the WithMaxEntries and WithMaxWeight
+	// limits; 0 means unbounded.
+	MaxEntries int
+	MaxWeight  int64
+	Weigher    bool
+	// JournalCapacity is the WithJournal capacity; 0 means no journal.
+	JournalCapacity int
+	// HistoryDepth is the WithHistoryDepth depth; 0 means no history.
+	HistoryDepth int
+	// StaleThreshold is the WithStaleThreshold age; 0 means never stale.
+	StaleThreshold time.Duration
+	// SlowReloadThreshold and SlowReloadFactor flag slow loads; 0 disables
+	// each check.
+	SlowReloadThreshold time.Duration
+	SlowReloadFactor    float64
+	// JanitorInterval is the WithJanitorInterval sweep interval; 0 means
+	// no janitor.
+	JanitorInterval time.Duration
+	// MaxDataAge is the WithMaxDataAge limit, 0 if none, and ExpirePolicy
+	// what happens once it passes.
+	MaxDataAge   time.Duration
+	ExpirePolicy ExpirePolicy
+
+	// StatsDimension reports whether WithStatsDimension is set.
+	StatsDimension bool
+
+	PrefixIndex bool
+	// Indexes lists the names of the secondary indexes, sorted.
+	Indexes              []string
+	Aliases              bool
+	ValueHasher          bool
+	BloomFilter          bool
+	Compression          bool
+	CompressionThreshold int
+	Cloner               bool
+	CloneOnAdd           bool
+	KeyNormalizer        bool
+	StableIteration      bool
+	Transform            bool
+	Validator            bool
+	RejectNil            bool
+	InvalidPolicy        InvalidPolicy
+}
+
+// Config returns the cache's effective configuration.
+func (c *Cache[T]) Config() Config {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	cfg := Config{
+		Name:                c.name,
+		Interval:            c.interval,
+		LogLevel:            c.logLevel,
+		DefaultTTL:          c.defaultTTL,
+		DefaultTTLOnLoad:    c.defaultTTLOnLoad,
+		SlidingTTL:          c.slidingTTL,
+		ChunkSize:           c.chunkSize,
+		ChunkedStorage:      c.chunkedStorageSize(),
+		MinReloadInterval:   c.minReload,
+		Scheduled:           c.schedule != nil,
+		StartDelay:          c.startDelay,
+		RandomStartDelay:    c.startJitter,
+		LoadOnStart:         c.loadOnStart,
+		CoalesceReloads:     c.coalesce,
+		NilResultError:      c.nilResultErr,
+		StaleThreshold:      c.staleAfter,
+		SlowReloadThreshold: c.slowAfter,
+		SlowReloadFactor:    c.slowFactor,
+		JanitorInterval:     c.janitorInterval,
+		HistoryDepth:        c.historyDepth,
+		MaxDataAge:          c.maxDataAge,
+		ExpirePolicy:        c.expirePolicy,
+		StatsDimension:      c.dims != nil,
+		PrefixIndex:         c.prefixIndex != nil,
+		Aliases:             c.aliases != nil,
+		ValueHasher:         c.valueIndex != nil,
+		BloomFilter:         c.bloom.Load() != nil,
+		Compression:         c.comp != nil && c.comp.codec != nil,
+		Cloner:              c.cloner != nil,
+		CloneOnAdd:          c.cloneOnAdd,
+		KeyNormalizer:       c.keyNorm != nil,
+		StableIteration:     c.stable,
+		Transform:           c.transform != nil,
+		Validator:           c.validator != nil,
+		RejectNil:           c.rejectNil,
+		InvalidPolicy:       c.invalidPolicy,
+	}
+	for name := range c.indexes {
+		cfg.Indexes = append(cfg.Indexes, name)
+	}
+	slices.Sort(cfg.Indexes)
+	if c.lru != nil {
+		cfg.MaxEntries = c.lru.max
+		cfg.MaxWeight = c.lru.maxWeight
+		cfg.Weigher = c.lru.weigher != nil
+	}
+	if c.partitions != nil {
+		cfg.Partitions = len(c.partitions.parts)
+		cfg.PartialFailurePolicy = c.partitions.policy
+	}
+	if c.journal != nil {
+		cfg.JournalCapacity = len(c.journal.ring)
+	}
+	if cfg.Compression {
+		cfg.CompressionThreshold = c.comp.threshold
+	}
+	return cfg
+}
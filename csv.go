@@ -0,0 +1,80 @@
+package cache
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+)
+
+// ExportCSV writes the live items to w as CSV, for inspection in a
+// spreadsheet: header first unless it is nil, then one record per item as
+// returned by rowFn, in key order. The items are taken from one consistent
+// snapshot, and rows are streamed to w as they are built.
+func (c *Cache[T]) ExportCSV(w io.Writer, header []string, rowFn func(key string, value T) []string) error {
+	snap := c.Snapshot()
+	keys := make([]string, 0, snap.Len())
+	snap.Range(func(key string, _ T) bool {
+		keys = append(keys, key)
+		return true
+	})
+	slices.Sort(keys)
+
+	cw := csv.NewWriter(w)
+	if header != nil {
+		if err := cw.Write(header); err != nil {
+			return err
+		}
+	}
+	for _, k := range keys {
+		v, _ := snap.Get(k)
+		if err := cw.Write(rowFn(k, v)); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// ImportCSV replaces the contents with the items parsed from the CSV
+// records in r, like SetAll: the new items are swapped in at once and the
+// import counts as a load. parse turns each record into an item, and may
+// return ErrSkipRecord to skip one, such as a header row. Records may have
+// differing numbers of fields. If reading or parsing any record fails, the
+// contents are left unchanged and the error names the record's line.
+// While the cache is frozen, ImportCSV returns ErrFrozen.
+func (c *Cache[T]) ImportCSV(r io.Reader, parse func(record []string) (string, T, error)) error {
+	if c.rejectFrozen("import") {
+		return ErrFrozen
+	}
+	cr := csv.NewReader(r)
+	cr.FieldsPerRecord = -1
+	items := make(map[string]T)
+	for {
+		record, err := cr.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return fmt.Errorf("cache: reading CSV: %w", err)
+		}
+		key, value, err := parse(record)
+		if errors.Is(err, ErrSkipRecord) {
+			continue
+		}
+		if err != nil {
+			line, _ := cr.FieldPos(0)
+			return fmt.Errorf("cache: CSV line %d: %w", line, err)
+		}
+		items[key] = value
+	}
+	items, err := c.validItems(items)
+	if err != nil {
+		return c.loadFailed(err)
+	}
+	if !c.install(c.loadEntries(items), nil) {
+		return ErrFrozen
+	}
+	return nil
+}
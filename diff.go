@@ -0,0 +1,45 @@
+package cache
+
+import "fmt"
+
+// reloadDiff summarizes how a reload changed the cache contents.
+type reloadDiff struct {
+	added, removed, changed int
+	// valuesCompared is false when no equality func is configured, in
+	// which case changed is always zero.
+	valuesCompared bool
+}
+
+// empty reports whether the reload left the contents unchanged.
+func (d reloadDiff) empty() bool {
+	return d.added == 0 && d.removed == 0 && d.changed == 0
+}
+
+// String formats the diff as "+added/-removed/~changed".
+func (d reloadDiff) String() string {
+	if !d.valuesCompared {
+		return fmt.Sprintf("+%d/-%d", d.added, d.removed)
+	}
+	return fmt.Sprintf("+%d/-%d/~%d", d.added, d.removed, d.changed)
+}
+
+// diffData compares two generations of cache contents. Without equal only
+// the key sets are compared, which keeps the cost to one map lookup per key.
+func diffData[T any](old, cur map[string]*entry[T], equal func(a, b T) bool) reloadDiff {
+	d := reloadDiff{valuesCompared: equal != nil}
+	for k, e := range cur {
+		prev, ok := old[k]
+		switch {
+		case !ok:
+			d.added++
+		case equal != nil && !equal(prev.val(), e.val()):
+			d.changed++
+		}
+	}
+	for k := range old {
+		if _, ok := cur[k]; !ok {
+			d.removed++
+		}
+	}
+	return d
+}
@@ -0,0 +1,127 @@
+package cache
+
+import (
+	"sync"
+	"sync/atomic"
+)
+
+// MaxStatsDimensions is the number of distinct dimension values tracked by
+// WithStatsDimension. Lookups and items for further values are counted
+// under OverflowDimension instead.
+const MaxStatsDimensions = 256
+
+// OverflowDimension is the StatsByDimension bucket collecting the values
+// beyond the first MaxStatsDimensions seen.
+const OverflowDimension = "(other)"
+
+// dimCounters holds the live statistics of one dimension value.
+type dimCounters struct {
+	hits   atomic.Uint64
+	misses atomic.Uint64
+}
+
+// dimensions tracks per-dimension statistics for WithStatsDimension.
+type dimensions struct {
+	of func(key string) string
+
+	mu       sync.RWMutex
+	byName   map[string]*dimCounters
+	overflow dimCounters
+}
+
+// bucket returns the name and counters for key's dimension value, starting
+// to track the value if there is room.
+func (d *dimensions) bucket(key string) (string, *dimCounters) {
+	name := d.of(key)
+	d.mu.RLock()
+	dc := d.byName[name]
+	d.mu.RUnlock()
+	if dc != nil {
+		return name, dc
+	}
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if dc = d.byName[name]; dc != nil {
+		return name, dc
+	}
+	if len(d.byName) >= MaxStatsDimensions {
+		return OverflowDimension, &d.overflow
+	}
+	dc = new(dimCounters)
+	d.byName[name] = dc
+	return name, dc
+}
+
+// record counts a lookup of key as a hit or a miss.
+func (d *dimensions) record(key string, hit bool) {
+	_, dc := d.bucket(key)
+	if hit {
+		dc.hits.Add(1)
+	} else {
+		dc.misses.Add(1)
+	}
+}
+
+// reset forgets every tracked value and its counts.
+func (d *dimensions) reset() {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.byName = make(map[string]*dimCounters)
+	d.overflow.hits.Store(0)
+	d.overflow.misses.Store(0)
+}
+
+// WithStatsDimension additionally aggregates hits, misses and item counts
+// per dimension of the key, as extracted by fn, for example a tenant
+// encoded in the key. See StatsByDimension. fn must be fast and must not
+// call back into the cache. At most MaxStatsDimensions values are tracked,
+// so a badly chosen fn cannot grow memory without bound.
+func WithStatsDimension[T any](fn func(key string) string) Option[T] {
+	return func(c *Cache[T]) {
+		if fn == nil {
+			c.dims = nil
+			return
+		}
+		c.dims = &dimensions{of: fn, byName: make(map[string]*dimCounters)}
+	}
+}
+
+// StatsByDimension returns the statistics of each dimension value seen per
+// WithStatsDimension, or nil without it. Only Hits, Misses, Items and
+// Since are filled in. Values beyond MaxStatsDimensions are summed under
+// OverflowDimension, present only once some are. ResetStats clears the
+// per-dimension counts along with the totals.
+func (c *Cache[T]) StatsByDimension() map[string]Stats {
+	if c.dims == nil {
+		return nil
+	}
+	now := c.clock.Now()
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	items := make(map[string]int)
+	for k, e := range c.data {
+		if !e.expired(now) {
+			name, _ := c.dims.bucket(k)
+			items[name]++
+		}
+	}
+
+	c.dims.mu.RLock()
+	defer c.dims.mu.RUnlock()
+	out := make(map[string]Stats, len(c.dims.byName)+1)
+	add := func(name string, dc *dimCounters) {
+		out[name] = Stats{
+			Hits:   dc.hits.Load(),
+			Misses: dc.misses.Load(),
+			Items:  items[name],
+			Since:  c.statsSince,
+		}
+	}
+	for name, dc := range c.dims.byName {
+		add(name, dc)
+	}
+	if s := &c.dims.overflow; s.hits.Load() > 0 || s.misses.Load() > 0 || items[OverflowDimension] > 0 {
+		add(OverflowDimension, s)
+	}
+	return out
+}
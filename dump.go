@@ -0,0 +1,73 @@
+package cache
+
+import (
+	"fmt"
+	"io"
+	"slices"
+	"time"
+)
+
+// String returns a one-line summary such as
+// "users: 1523 items, loaded 12s ago", flagged "(stale)" or "(expired)"
+// once the data outlives WithStaleThreshold or WithMaxDataAge.
+func (c *Cache[T]) String() string {
+	h := c.Health()
+	name := c.name
+	if name == "" {
+		name = "cache"
+	}
+	var s string
+	if h.Loaded {
+		s = fmt.Sprintf("%s: %d items, loaded %s ago", name, h.Items, h.SinceLastLoad.Round(time.Second))
+	} else {
+		s = fmt.Sprintf("%s: %d items, never loaded", name, h.Items)
+	}
+	if h.Freshness == Stale || h.Freshness == Expired || h.Freshness == Bootstrapped {
+		s += " (" + h.Freshness.String() + ")"
+	}
+	if h.Frozen {
+		s += " (frozen)"
+	}
+	if h.Suspended {
+		s += " (suspended)"
+	}
+	return s
+}
+
+// Dump writes a human-readable listing of up to maxItems live items, sorted
+// by key, followed by the String summary. Values are rendered with %+v so
+// unexported fields are visible. Formatting happens on a Snapshot, so no
+// cache lock is held while writing. A maxItems < 0 dumps every item.
+func (c *Cache[T]) Dump(w io.Writer, maxItems int) error {
+	snap := c.Snapshot()
+	keys := make([]string, 0, len(snap.data))
+	for k, e := range snap.data {
+		if !e.expired(snap.at) {
+			keys = append(keys, k)
+		}
+	}
+	slices.Sort(keys)
+	shown := keys
+	if maxItems >= 0 && len(shown) > maxItems {
+		shown = shown[:maxItems]
+	}
+	for _, k := range shown {
+		e := snap.data[k]
+		var err error
+		if exp := e.expiresAt.Load(); exp != 0 {
+			_, err = fmt.Fprintf(w, "%s = %+v (expires in %s)\n", k, snap.output(e.val()), time.Unix(0, exp).Sub(snap.at).Round(time.Millisecond))
+		} else {
+			_, err = fmt.Fprintf(w, "%s = %+v\n", k, snap.output(e.val()))
+		}
+		if err != nil {
+			return err
+		}
+	}
+	if omitted := len(keys) - len(shown); omitted > 0 {
+		if _, err := fmt.Fprintf(w, "... %d more items\n", omitted); err != nil {
+			return err
+		}
+	}
+	_, err := fmt.Fprintln(w, c.String())
+	return err
+}
@@ -0,0 +1,56 @@
+package cache
+
+import (
+	"container/list"
+	"sync/atomic"
+	"time"
+)
+
+// entry wraps a cached value with its expiration time. The expiration is
+// stored atomically so it can be extended while holding only the read lock.
+type entry[T any] struct {
+	value     T
+	packed    *packedValue[T] // non-nil when the value is stored compressed
+	expiresAt atomic.Int64    // unix nanoseconds; 0 means no expiry
+	stamp     uint64          // load stamp when stored; guarded by Cache.mu
+	source    Source
+	version   uint64        // see GetVersioned; guarded by Cache.mu
+	weight    int64         // see WithWeigher; guarded by Cache.mu
+	elem      *list.Element // position in the LRU list; guarded by lru.mu
+}
+
+// newEntry builds an entry for value expiring at the given unix-nano time.
+func newEntry[T any](value T, expiresAt int64) *entry[T] {
+	e := &entry[T]{value: value}
+	e.expiresAt.Store(expiresAt)
+	return e
+}
+
+// val returns the entry's value, decompressing it if necessary.
+func (e *entry[T]) val() T {
+	if e.packed == nil {
+		return e.value
+	}
+	return e.packed.unpack()
+}
+
+// expired reports whether the entry's expiration lies at or before now.
+func (e *entry[T]) expired(now time.Time) bool {
+	exp := e.expiresAt.Load()
+	return exp != 0 && exp <= now.UnixNano()
+}
+
+// expiryFor converts a ttl relative to now into a unix-nano expiration,
+// returning 0 (no expiry) for ttl <= 0.
+func expiryFor(now time.Time, ttl time.Duration) int64 {
+	if ttl <= 0 {
+		return 0
+	}
+	return now.Add(ttl).UnixNano()
+}
+
+// Entry is a key paired with its cached value.
+type Entry[T any] struct {
+	Key   string
+	Value T
+}
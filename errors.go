@@ -0,0 +1,72 @@
+package cache
+
+import "errors"
+
+// ErrNotFound is returned, wrapped with the key, by GetE when a key is
+// absent or expired.
+var ErrNotFound = errors.New("cache: key not found")
+
+// ErrLoaderPanic wraps panics recovered from a loader, so that a panicking
+// loader is reported like any other failed load.
+var ErrLoaderPanic = errors.New("cache: loader panicked")
+
+// ErrCallbackPanic wraps panics recovered from callbacks run on worker
+// goroutines, such as those passed to ForEachParallel.
+var ErrCallbackPanic = errors.New("cache: callback panicked")
+
+// ErrDuplicateKey is returned by UniqueSliceLoader when two elements map to
+// the same key.
+var ErrDuplicateKey = errors.New("cache: duplicate key")
+
+// ErrScheduleSet is returned by SetInterval for caches reloading on a
+// WithSchedule schedule.
+var ErrScheduleSet = errors.New("cache: reload schedule set; interval does not apply")
+
+// ErrReloadThrottled is returned by Reload when WithMinReloadInterval
+// rejects a call made too soon after the previous load.
+var ErrReloadThrottled = errors.New("cache: reload throttled")
+
+// ErrVersionMismatch is returned by AddIfVersion when the key's current
+// version is not the expected one.
+var ErrVersionMismatch = errors.New("cache: version mismatch")
+
+// ErrNoBatchLoader is returned by GetOrLoadMany when keys are missing and
+// no WithBatchKeyLoader loader is set.
+var ErrNoBatchLoader = errors.New("cache: no batch key loader")
+
+// ErrInvalidValue wraps the rejection of a value by WithTransform,
+// WithValueValidator or WithRejectNil.
+var ErrInvalidValue = errors.New("cache: invalid value")
+
+// ErrFrozen is returned by Load and by error-returning writes while the
+// cache is frozen; see Freeze.
+var ErrFrozen = errors.New("cache: frozen")
+
+// ErrSkipRecord may be returned by the parse func given to ImportCSV to
+// skip a record, such as a header row.
+var ErrSkipRecord = errors.New("cache: skip record")
+
+// ErrReloadPending is returned by PrepareReload while an earlier prepared
+// reload has been neither committed nor discarded.
+var ErrReloadPending = errors.New("cache: a prepared reload is pending")
+
+// ErrStaleReload is returned by Pending.Commit when the cache has
+// loaded newer data since the reload was prepared, or the reload was
+// already committed or discarded.
+var ErrStaleReload = errors.New("cache: prepared reload is stale")
+
+// ErrNilResult is the load error for a loader returning a nil map and a
+// nil error, with WithNilResultError set.
+var ErrNilResult = errors.New("cache: loader returned a nil map")
+
+// ErrHistoryEvicted is returned by GetAt and GetFrom for a point older
+// than the oldest load kept by WithHistoryDepth, or when no history is
+// kept, as distinct from the key being absent at that point.
+var ErrHistoryEvicted = errors.New("cache: history evicted")
+
+// ErrWriteBehindDropped is reported by FlushWriteBehind when the
+// DropWhenFull policy dropped ops since the previous call.
+var ErrWriteBehindDropped = errors.New("cache: write-behind ops dropped")
+
+// ErrUnnamed is returned by PublishExpvar for a cache without WithName.
+var ErrUnnamed = errors.New("cache: cache has no name")
@@ -0,0 +1,83 @@
+package cache
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+)
+
+// exportRecord is the serialized form of one item written by Export.
+type exportRecord[T any] struct {
+	Key       string `json:"key"`
+	Value     T      `json:"value"`
+	ExpiresAt int64  `json:"expiresAt,omitempty"` // unix nanoseconds
+	Manual    bool   `json:"manual,omitempty"`
+}
+
+// Export writes every live item to w as JSON, one object per line, with its
+// expiry and source, so that Import can restore the cache faithfully.
+// Unlike MarshalJSON, expiring items do not come back as permanent.
+func (c *Cache[T]) Export(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	for _, m := range c.EntriesWithMeta() {
+		rec := exportRecord[T]{Key: m.Key, Value: m.Value, Manual: m.Source == SourceManual}
+		if !m.ExpiresAt.IsZero() {
+			rec.ExpiresAt = m.ExpiresAt.UnixNano()
+		}
+		if err := enc.Encode(rec); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Import replaces the contents with items written by Export, keeping each
+// item's expiry and source. Items that have expired by now are dropped.
+// Like Prime, an import does not count as a load, and its items are
+// validated as a load's would be. On error the contents are unchanged.
+// Import returns the number of items restored.
+func (c *Cache[T]) Import(r io.Reader) (int, error) {
+	if c.rejectFrozen("import") {
+		return 0, ErrFrozen
+	}
+	now := c.clock.Now().UnixNano()
+	recs := make(map[string]exportRecord[T])
+	dec := json.NewDecoder(r)
+	for {
+		var rec exportRecord[T]
+		if err := dec.Decode(&rec); err != nil {
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			return 0, err
+		}
+		if rec.ExpiresAt != 0 && rec.ExpiresAt <= now {
+			continue
+		}
+		recs[c.normKey(rec.Key)] = rec
+	}
+	items := make(map[string]T, len(recs))
+	for k, rec := range recs {
+		items[k] = rec.Value
+	}
+	items, err := c.validItems(items)
+	if err != nil {
+		return 0, err
+	}
+	data := make(map[string]*entry[T], len(items))
+	for k, v := range items {
+		e := c.makeEntry(v, recs[k].ExpiresAt)
+		if recs[k].Manual {
+			e.source = SourceManual
+		}
+		data[k] = e
+	}
+	c.mu.Lock()
+	c.stripTombstonedLocked(data)
+	c.replaceLocked(data)
+	evicted := c.evictLocked()
+	h := c.hooks
+	c.mu.Unlock()
+	c.notify(h, evicted)
+	return len(data), nil
+}
@@ -0,0 +1,67 @@
+package cache_test
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/TheOrchestraX/cache"
+)
+
+func TestExportImportKeepsExpiry(t *testing.T) {
+	src, _ := newTestCache(t, map[string]int{"permanent": 1})
+	src.AddWithTTL("brief", 2, time.Minute)
+	src.AddWithTTL("hourly", 3, time.Hour)
+	var buf bytes.Buffer
+	if err := src.Export(&buf); err != nil {
+		t.Fatal(err)
+	}
+
+	// Restore ten minutes later, after "brief" has lapsed.
+	dst, clk := newTestCache(t, map[string]int{})
+	clk.Advance(10 * time.Minute)
+	n, err := dst.Import(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != 2 {
+		t.Fatalf("Import restored %d items, want 2", n)
+	}
+	mustMiss(t, dst, "brief")
+
+	perm, ok := dst.GetWithMeta("permanent")
+	if !ok || perm.Value != 1 || !perm.ExpiresAt.IsZero() || perm.Source != cache.SourceLoader {
+		t.Fatalf("permanent = %+v, %v; want 1, no expiry, from the loader", perm, ok)
+	}
+	hourly, ok := dst.GetWithMeta("hourly")
+	if want := epoch.Add(time.Hour); !ok || !hourly.ExpiresAt.Equal(want) || hourly.Source != cache.SourceManual {
+		t.Fatalf("hourly = %+v, %v; want expiry %s, manual", hourly, ok, want)
+	}
+
+	clk.Advance(time.Hour)
+	mustMiss(t, dst, "hourly")
+	if v := mustGet(t, dst, "permanent"); v != 1 {
+		t.Fatalf("Get(permanent) = %d, want 1", v)
+	}
+}
+
+func TestEntriesWithMeta(t *testing.T) {
+	c, clk := newTestCache(t, map[string]int{"a": 1})
+	c.AddWithTTL("b", 2, time.Minute)
+	c.AddWithTTL("gone", 3, time.Second)
+	clk.Advance(2 * time.Second)
+
+	got := make(map[string]cache.EntryMeta[int])
+	for _, m := range c.EntriesWithMeta() {
+		got[m.Key] = m
+	}
+	if len(got) != 2 {
+		t.Fatalf("EntriesWithMeta returned %d entries, want 2: %v", len(got), got)
+	}
+	if a := got["a"]; a.Value != 1 || !a.ExpiresAt.IsZero() || a.Source != cache.SourceLoader {
+		t.Fatalf("a = %+v", a)
+	}
+	if b := got["b"]; b.Value != 2 || !b.ExpiresAt.Equal(epoch.Add(time.Minute)) || b.Source != cache.SourceManual {
+		t.Fatalf("b = %+v", b)
+	}
+}
@@ -0,0 +1,30 @@
+package cache
+
+import (
+	"expvar"
+	"fmt"
+	"sync"
+)
+
+// expvarMu serializes PublishExpvar, as expvar.Publish panics on a name
+// that is already taken.
+var expvarMu sync.Mutex
+
+// PublishExpvar publishes the cache's Stats with package expvar under
+// "cache.<name>", so they are served at /debug/vars alongside the other
+// process variables. The Stats are read afresh on every request. The cache
+// must be named with WithName; an unnamed cache returns ErrUnnamed, and a
+// name that is already published returns an error.
+func (c *Cache[T]) PublishExpvar() error {
+	if c.name == "" {
+		return ErrUnnamed
+	}
+	key := "cache." + c.name
+	expvarMu.Lock()
+	defer expvarMu.Unlock()
+	if expvar.Get(key) != nil {
+		return fmt.Errorf("cache: expvar %q already published", key)
+	}
+	expvar.Publish(key, expvar.Func(func() any { return c.Stats() }))
+	return nil
+}
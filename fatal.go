@@ -0,0 +1,52 @@
+package cache
+
+import "errors"
+
+// fatalError marks a loader error as not worth retrying; see Fatal.
+type fatalError struct {
+	err error
+}
+
+func (e *fatalError) Error() string   { return e.err.Error() }
+func (e *fatalError) Unwrap() error   { return e.err }
+func (e *fatalError) Retryable() bool { return false }
+
+// Fatal wraps err, returned by a loader, to mark it as not worth retrying,
+// such as an authentication or schema error. A failed load whose error is
+// fatal suspends auto-reload: scheduled reloads are skipped, and Health
+// reports Suspended, until a manual Load succeeds or SetLoader or
+// SetLoaderContext installs a new loader. Errors may also classify
+// themselves by implementing Retryable() bool. Unclassified errors are
+// retryable and keep the normal reload schedule. Fatal(nil) returns nil.
+func Fatal(err error) error {
+	if err == nil {
+		return nil
+	}
+	return &fatalError{err: err}
+}
+
+// IsFatal reports whether err, or an error it wraps, has a Retryable
+// method returning false, as errors made by Fatal do. OnLoadError hooks
+// can use it to tell fatal failures from transient ones.
+func IsFatal(err error) bool {
+	var r interface{ Retryable() bool }
+	return errors.As(err, &r) && !r.Retryable()
+}
+
+// suspendLocked suspends auto-reload after a fatal load error. The caller
+// must hold c.mu for writing.
+func (c *Cache[T]) suspendLocked(err error) {
+	if !c.suspended {
+		c.suspended = true
+		c.logf(LogErrorsOnly, "%s fatal load error; suspending auto-reload until a manual load succeeds: %v", c.label(), err)
+	}
+}
+
+// resumeLocked lifts a suspension, if any. The caller must hold c.mu for
+// writing.
+func (c *Cache[T]) resumeLocked() {
+	if c.suspended {
+		c.suspended = false
+		c.logf(LogInfo, "%s auto-reload resumed", c.label())
+	}
+}
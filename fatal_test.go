@@ -0,0 +1,185 @@
+package cache_test
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/TheOrchestraX/cache"
+	"github.com/TheOrchestraX/cache/cachetest"
+)
+
+// classified is an error classifying itself through Retryable.
+type classified struct{ retry bool }
+
+func (e classified) Error() string   { return fmt.Sprintf("classified(retry=%v)", e.retry) }
+func (e classified) Retryable() bool { return e.retry }
+
+func TestIsFatal(t *testing.T) {
+	base := errors.New("bad credentials")
+	for _, tc := range []struct {
+		err  error
+		want bool
+	}{
+		{base, false},
+		{cache.Fatal(base), true},
+		{fmt.Errorf("loading: %w", cache.Fatal(base)), true},
+		{classified{retry: false}, true},
+		{classified{retry: true}, false},
+		{fmt.Errorf("loading: %w", classified{retry: true}), false},
+	} {
+		if got := cache.IsFatal(tc.err); got != tc.want {
+			t.Errorf("IsFatal(%v) = %v, want %v", tc.err, got, tc.want)
+		}
+	}
+	if !errors.Is(cache.Fatal(base), base) {
+		t.Error("Fatal(err) does not wrap err")
+	}
+	if cache.Fatal(nil) != nil {
+		t.Error("Fatal(nil) != nil")
+	}
+}
+
+// classifiedLoader is an auto-reloading cache on a fake clock whose loader
+// fails with whatever error is set.
+type classifiedLoader struct {
+	c     *cache.Cache[int]
+	clk   *cachetest.FakeClock
+	loads atomic.Int32
+
+	mu       sync.Mutex
+	err      error
+	reported []error // errors seen by OnLoadError
+}
+
+func newClassifiedLoader(t *testing.T) *classifiedLoader {
+	l := &classifiedLoader{clk: cachetest.NewFakeClock(epoch)}
+	l.c = cache.NewCache(func() (map[string]int, error) {
+		l.loads.Add(1)
+		l.mu.Lock()
+		defer l.mu.Unlock()
+		if l.err != nil {
+			return nil, l.err
+		}
+		return map[string]int{"a": 1}, nil
+	}, time.Minute, cache.WithClock[int](l.clk), cache.WithLogLevel[int](cache.LogSilent))
+	l.c.OnLoadError(func(err error, _ int) {
+		l.mu.Lock()
+		l.reported = append(l.reported, err)
+		l.mu.Unlock()
+	})
+	if err := l.c.Load(); err != nil {
+		t.Fatal(err)
+	}
+	l.c.StartAutoReload()
+	t.Cleanup(l.c.StopAutoReload)
+	return l
+}
+
+func (l *classifiedLoader) fail(err error) {
+	l.mu.Lock()
+	l.err = err
+	l.mu.Unlock()
+}
+
+// tick fires the reload timer and waits for the loop to re-arm it, which
+// it does once any load has returned.
+func (l *classifiedLoader) tick(t *testing.T) {
+	t.Helper()
+	waitFor(t, "the reload timer", func() bool { return l.clk.Waiters() > 0 })
+	l.clk.Advance(time.Minute)
+	waitFor(t, "the reload timer to re-arm", func() bool { return l.clk.Waiters() > 0 })
+}
+
+func (l *classifiedLoader) lastReported() error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if len(l.reported) == 0 {
+		return nil
+	}
+	return l.reported[len(l.reported)-1]
+}
+
+func TestRetryableErrorKeepsSchedule(t *testing.T) {
+	l := newClassifiedLoader(t)
+	for _, err := range []error{errors.New("timeout"), classified{retry: true}} {
+		l.fail(err)
+		before := l.loads.Load()
+		l.tick(t)
+		l.tick(t)
+		if n := l.loads.Load() - before; n != 2 {
+			t.Fatalf("%v: %d scheduled loads, want 2", err, n)
+		}
+		if h := l.c.Health(); h.Suspended {
+			t.Fatalf("%v: Health.Suspended = true for a retryable error", err)
+		}
+		if got := l.lastReported(); got != err || cache.IsFatal(got) {
+			t.Fatalf("OnLoadError got %v, want retryable %v", got, err)
+		}
+	}
+}
+
+func TestFatalErrorSuspendsUntilManualLoad(t *testing.T) {
+	l := newClassifiedLoader(t)
+	l.fail(cache.Fatal(errors.New("bad credentials")))
+	l.tick(t)
+	if h := l.c.Health(); !h.Suspended || h.ConsecutiveFailures != 1 {
+		t.Fatalf("Health = %+v, want suspended after one failure", h)
+	}
+	if !cache.IsFatal(l.lastReported()) {
+		t.Fatalf("OnLoadError got %v, want a fatal error", l.lastReported())
+	}
+	before := l.loads.Load()
+	l.tick(t)
+	l.tick(t)
+	if n := l.loads.Load() - before; n != 0 {
+		t.Fatalf("%d scheduled loads while suspended, want 0", n)
+	}
+	if v := mustGet(t, l.c, "a"); v != 1 {
+		t.Fatalf("Get(a) = %d, want the old data kept", v)
+	}
+
+	// A failing manual load keeps the suspension; a successful one lifts it.
+	if err := l.c.Load(); !cache.IsFatal(err) || !l.c.Health().Suspended {
+		t.Fatalf("manual Load = %v, suspended %v; want still suspended", err, l.c.Health().Suspended)
+	}
+	l.fail(nil)
+	if err := l.c.Load(); err != nil {
+		t.Fatal(err)
+	}
+	if h := l.c.Health(); h.Suspended || h.ConsecutiveFailures != 0 {
+		t.Fatalf("Health = %+v, want resumed", h)
+	}
+	before = l.loads.Load()
+	l.tick(t)
+	if n := l.loads.Load() - before; n != 1 {
+		t.Fatalf("%d scheduled loads after resuming, want 1", n)
+	}
+}
+
+func TestFatalErrorSuspendsUntilSetLoader(t *testing.T) {
+	l := newClassifiedLoader(t)
+	l.fail(classified{retry: false})
+	l.tick(t)
+	if !l.c.Health().Suspended {
+		t.Fatal("not suspended after a fatal error")
+	}
+	var replaced atomic.Int32
+	l.c.SetLoader(func() (map[string]int, error) {
+		replaced.Add(1)
+		return map[string]int{"a": 2}, nil
+	})
+	if l.c.Health().Suspended {
+		t.Fatal("still suspended after SetLoader")
+	}
+	l.tick(t)
+	if n := replaced.Load(); n != 1 {
+		t.Fatalf("new loader ran %d times, want 1", n)
+	}
+	if v := mustGet(t, l.c, "a"); v != 2 {
+		t.Fatalf("Get(a) = %d, want 2", v)
+	}
+}
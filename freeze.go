@@ -0,0 +1,38 @@
+package cache
+
+// Freeze makes the cache read-only, for keeping its data exactly as it is
+// while an incident upstream is investigated. Until Unfreeze, loads and
+// writes are ignored: Load returns ErrFrozen without calling the loader,
+// error-returning writes such as AddE, AddIfVersion and Import return
+// ErrFrozen, and the others do nothing. Each ignored call is logged and
+// counted in Stats.FrozenRejections. Reads continue normally, and a
+// running auto-reload keeps its schedule, its loads being ignored.
+func (c *Cache[T]) Freeze() {
+	if !c.frozen.Swap(true) {
+		c.logf(LogInfo, "%s frozen", c.label())
+	}
+}
+
+// Unfreeze lifts Freeze. Auto-reload, if running, resumes at its next
+// scheduled reload.
+func (c *Cache[T]) Unfreeze() {
+	if c.frozen.Swap(false) {
+		c.logf(LogInfo, "%s unfrozen", c.label())
+	}
+}
+
+// IsFrozen reports whether the cache is frozen.
+func (c *Cache[T]) IsFrozen() bool {
+	return c.frozen.Load()
+}
+
+// rejectFrozen reports whether the cache is frozen, recording that op was
+// ignored if it is.
+func (c *Cache[T]) rejectFrozen(op string) bool {
+	if !c.frozen.Load() {
+		return false
+	}
+	c.stats.frozenRejections.Add(1)
+	c.logf(LogInfo, "%s is frozen; %s ignored", c.label(), op)
+	return true
+}
@@ -0,0 +1,65 @@
+package cache_test
+
+import (
+	"errors"
+	"maps"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/TheOrchestraX/cache"
+	"github.com/TheOrchestraX/cache/cachetest"
+)
+
+func TestFrozenReloadKeepsDataAndLastLoaded(t *testing.T) {
+	clk := cachetest.NewFakeClock(epoch)
+	var calls atomic.Int32
+	var version atomic.Int32
+	version.Store(1)
+	c := cache.NewCache(func() (map[string]int, error) {
+		calls.Add(1)
+		return map[string]int{"v": int(version.Load())}, nil
+	}, time.Minute, cache.WithClock[int](clk), cache.WithLogLevel[int](cache.LogSilent))
+	if err := c.Load(); err != nil {
+		t.Fatal(err)
+	}
+	c.StartAutoReload()
+	defer c.StopAutoReload()
+
+	c.Freeze()
+	version.Store(2)
+	clk.Advance(30 * time.Second)
+	if err := c.Load(); !errors.Is(err, cache.ErrFrozen) {
+		t.Fatalf("Load while frozen = %v, want ErrFrozen", err)
+	}
+	// The scheduled reload fires and is ignored too.
+	waitFor(t, "the reload timer", func() bool { return clk.Waiters() > 0 })
+	clk.Advance(time.Minute)
+	waitFor(t, "the reload timer to re-arm", func() bool { return clk.Waiters() > 0 })
+	c.Add("w", 1)
+
+	if n := calls.Load(); n != 1 {
+		t.Fatalf("loader called %d times, want only the initial load", n)
+	}
+	if got := c.GetAll(); !maps.Equal(got, map[string]int{"v": 1}) {
+		t.Fatalf("GetAll = %v while frozen, want the original data", got)
+	}
+	h := c.Health()
+	if !h.Frozen || !h.LastLoaded.Equal(epoch) || h.SinceLastLoad != 90*time.Second {
+		t.Fatalf("Health = %+v, want frozen with LastLoaded still %s", h, epoch)
+	}
+	if n := c.Stats().FrozenRejections; n != 3 {
+		t.Fatalf("FrozenRejections = %d, want 3", n)
+	}
+
+	c.Unfreeze()
+	if err := c.Load(); err != nil {
+		t.Fatal(err)
+	}
+	if v := mustGet(t, c, "v"); v != 2 {
+		t.Fatalf("Get(v) = %d after Unfreeze, want 2", v)
+	}
+	if h := c.Health(); !h.LastLoaded.Equal(clk.Now()) {
+		t.Fatalf("LastLoaded = %s, want %s", h.LastLoaded, clk.Now())
+	}
+}
@@ -0,0 +1,86 @@
+package cache
+
+import (
+	"fmt"
+	"time"
+)
+
+// Freshness classifies the age of a cache's data.
+type Freshness int
+
+const (
+	// NeverLoaded means no load has succeeded yet; any data was primed,
+	// imported or added by hand.
+	NeverLoaded Freshness = iota
+	// Fresh means the last successful load is within the stale threshold,
+	// or no threshold is set.
+	Fresh
+	// Stale means the last successful load is older than the threshold
+	// set by WithStaleThreshold, typically because the loader is failing.
+	// The data is still served.
+	Stale
+	// Expired means the last successful load is older than the
+	// WithMaxDataAge limit. Depending on the ExpirePolicy the data has been
+	// cleared or is served flagged.
+	Expired
+	// Bootstrapped means no load has succeeded yet and the contents are
+	// the fallback installed by WithBootstrapData.
+	Bootstrapped
+)
+
+// String returns the freshness's name.
+func (f Freshness) String() string {
+	switch f {
+	case NeverLoaded:
+		return "never-loaded"
+	case Fresh:
+		return "fresh"
+	case Stale:
+		return "stale"
+	case Expired:
+		return "expired"
+	case Bootstrapped:
+		return "bootstrapped"
+	}
+	return fmt.Sprintf("Freshness(%d)", int(f))
+}
+
+// WithStaleThreshold marks the cache's data Stale once d has passed since
+// the last successful load. Stale data is still served; GetWithFreshness
+// and Health report the classification so callers can flag it.
+func WithStaleThreshold[T any](d time.Duration) Option[T] {
+	return func(c *Cache[T]) {
+		c.staleAfter = d
+	}
+}
+
+// GetWithFreshness is Get, also reporting the freshness of the cache's
+// data so that callers can, say, warn that a value may be outdated.
+func (c *Cache[T]) GetWithFreshness(key string) (T, Freshness, bool) {
+	v, ok := c.Get(key)
+	return v, c.Freshness(), ok
+}
+
+// Freshness reports the freshness of the cache's data.
+func (c *Cache[T]) Freshness() Freshness {
+	now := c.clock.Now()
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.freshnessLocked(now)
+}
+
+// freshnessLocked classifies the data's age at now. The caller must hold
+// c.mu.
+func (c *Cache[T]) freshnessLocked(now time.Time) Freshness {
+	switch {
+	case c.lastLoaded.IsZero() && c.bootstrapped:
+		return Bootstrapped
+	case c.lastLoaded.IsZero():
+		return NeverLoaded
+	case c.dataExpiredLocked(now):
+		return Expired
+	case c.staleAfter > 0 && now.Sub(c.lastLoaded) > c.staleAfter:
+		return Stale
+	}
+	return Fresh
+}
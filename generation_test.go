@@ -0,0 +1,45 @@
+package cache_test
+
+import (
+	"errors"
+	"maps"
+	"testing"
+
+	"github.com/TheOrchestraX/cache"
+)
+
+func TestGenerationIncrements(t *testing.T) {
+	data := map[string]int{"a": 1}
+	var loadErr error
+	c := cache.NewCache(func() (map[string]int, error) { return maps.Clone(data), loadErr }, 0,
+		cache.WithLogLevel[int](cache.LogSilent))
+	if g := c.Generation(); g != 0 {
+		t.Fatalf("Generation before any load = %d, want 0", g)
+	}
+	steps := []struct {
+		name string
+		op   func()
+		want uint64 // increment
+	}{
+		{"Load", func() { c.Load() }, 1},
+		{"Load unchanged", func() { c.Load() }, 1},
+		{"failed Load", func() { loadErr = errors.New("down"); c.Load(); loadErr = nil }, 0},
+		{"SetAll", func() { c.SetAll(map[string]int{"a": 1, "b": 2}) }, 1},
+		{"Add new", func() { c.Add("c", 3) }, 1},
+		{"Add existing", func() { c.Add("c", 4) }, 1},
+		{"Delete", func() { c.Delete("c") }, 1},
+		{"Delete absent", func() { c.Delete("c") }, 0},
+		{"Get", func() { c.Get("a") }, 0},
+		{"Clear", func() { c.Clear() }, 1},
+	}
+	for _, s := range steps {
+		before := c.Generation()
+		s.op()
+		if got := c.Generation() - before; got != s.want {
+			t.Fatalf("%s advanced Generation by %d, want %d", s.name, got, s.want)
+		}
+	}
+	if s := c.Snapshot(); s.Generation() != c.Generation() {
+		t.Fatalf("Snapshot().Generation() = %d, want %d", s.Generation(), c.Generation())
+	}
+}
@@ -0,0 +1,166 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// Reloadable is the non-generic lifecycle surface of a cache, letting
+// caches of different value types be managed together. *Cache[T]
+// implements it for every T.
+type Reloadable interface {
+	Load() error
+	LoadContext(ctx context.Context) error
+	StartAutoReload()
+	StopAutoReload()
+	Health() HealthStatus
+}
+
+var _ Reloadable = (*Cache[int])(nil)
+
+// Group manages the lifecycle of several named caches together.
+type Group struct {
+	mu             sync.Mutex
+	names          []string
+	caches         map[string]Reloadable
+	maxConcurrency int
+}
+
+// NewGroup returns an empty Group. ReloadAll runs at most maxConcurrency
+// loads at a time; maxConcurrency <= 0 means no limit.
+func NewGroup(maxConcurrency int) *Group {
+	return &Group{
+		caches:         make(map[string]Reloadable),
+		maxConcurrency: maxConcurrency,
+	}
+}
+
+// Add registers c under name, replacing any cache already registered under
+// that name.
+func (g *Group) Add(name string, c Reloadable) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if _, exists := g.caches[name]; !exists {
+		g.names = append(g.names, name)
+	}
+	g.caches[name] = c
+}
+
+// StartAll starts auto-reload on every cache, in registration order.
+func (g *Group) StartAll() {
+	for _, m := range g.members() {
+		m.cache.StartAutoReload()
+	}
+}
+
+// StartAllStaggered starts auto-reload on every cache, in registration
+// order, spacing their first reloads evenly over spread so that caches
+// started together do not reload in lockstep. Caches that cannot delay
+// their first reload are started as by StartAll.
+func (g *Group) StartAllStaggered(spread time.Duration) {
+	members := g.members()
+	for i, m := range members {
+		if s, ok := m.cache.(interface{ StartAutoReloadAfter(time.Duration) }); ok {
+			s.StartAutoReloadAfter(spread * time.Duration(i+1) / time.Duration(len(members)))
+			continue
+		}
+		m.cache.StartAutoReload()
+	}
+}
+
+// StopAll stops auto-reload on every cache, in registration order.
+func (g *Group) StopAll() {
+	for _, m := range g.members() {
+		m.cache.StopAutoReload()
+	}
+}
+
+// ReloadAll loads every cache in parallel, bounded by the group's
+// concurrency limit, and returns the failures joined with errors.Join, each
+// prefixed with its cache name. Each load runs under ctx via LoadContext,
+// so cancelling ctx also cancels the loads in flight; once ctx is done no
+// further loads are started and ctx's error is included in the result.
+func (g *Group) ReloadAll(ctx context.Context) error {
+	members := g.members()
+	loads := make([]namedLoad, len(members))
+	for i, m := range members {
+		loads[i] = namedLoad{name: m.name, load: m.cache.LoadContext}
+	}
+	return runLoads(ctx, loads, g.maxConcurrency, nil)
+}
+
+// Health returns the health of every cache, keyed by name.
+func (g *Group) Health() map[string]HealthStatus {
+	members := g.members()
+	result := make(map[string]HealthStatus, len(members))
+	for _, m := range members {
+		result[m.name] = m.cache.Health()
+	}
+	return result
+}
+
+// namedLoad is a load function labelled for error messages and progress.
+type namedLoad struct {
+	name string
+	load func(ctx context.Context) error
+}
+
+// runLoads runs loads in parallel under ctx, at most limit at a time (no
+// limit when limit <= 0), calling onDone serially after each completes.
+// Failures are prefixed with the load's name and joined; once ctx is done
+// no further loads start and ctx's error is appended.
+func runLoads(ctx context.Context, loads []namedLoad, limit int, onDone func(name string, err error)) error {
+	if limit <= 0 {
+		limit = len(loads)
+	}
+	sem := make(chan struct{}, max(limit, 1))
+	errs := make([]error, len(loads))
+	var (
+		wg     sync.WaitGroup
+		doneMu sync.Mutex
+	)
+	for i, l := range loads {
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
+			wg.Wait()
+			return errors.Join(append(errs, ctx.Err())...)
+		}
+		wg.Add(1)
+		go func() {
+			defer func() { <-sem; wg.Done() }()
+			err := l.load(ctx)
+			if err != nil {
+				errs[i] = fmt.Errorf("%s: %w", l.name, err)
+			}
+			if onDone != nil {
+				doneMu.Lock()
+				onDone(l.name, err)
+				doneMu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+	return errors.Join(errs...)
+}
+
+// groupMember is a registered cache paired with its name.
+type groupMember struct {
+	name  string
+	cache Reloadable
+}
+
+// members returns the registered caches in registration order, so that
+// callbacks run without holding the group lock.
+func (g *Group) members() []groupMember {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	members := make([]groupMember, len(g.names))
+	for i, name := range g.names {
+		members[i] = groupMember{name: name, cache: g.caches[name]}
+	}
+	return members
+}
@@ -0,0 +1,51 @@
+package cache_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/TheOrchestraX/cache"
+)
+
+type ctxKey struct{}
+
+func TestGroupReloadAllPassesContext(t *testing.T) {
+	var got []any
+	loader := func(ctx context.Context) (map[string]int, error) {
+		got = append(got, ctx.Value(ctxKey{}))
+		return map[string]int{"a": 1}, nil
+	}
+	g := cache.NewGroup(1)
+	g.Add("a", cache.NewCacheWithContext(loader, 0, cache.WithLogLevel[int](cache.LogSilent)))
+	g.Add("b", cache.NewCacheWithContext(loader, 0, cache.WithLogLevel[int](cache.LogSilent)))
+	ctx := context.WithValue(context.Background(), ctxKey{}, "req")
+	if err := g.ReloadAll(ctx); err != nil {
+		t.Fatalf("ReloadAll: %v", err)
+	}
+	if len(got) != 2 || got[0] != "req" || got[1] != "req" {
+		t.Fatalf("loaders saw context values %v, want [req req]", got)
+	}
+}
+
+func TestGroupReloadAllCancelsInFlight(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	started := make(chan struct{})
+	c := cache.NewCacheWithContext(func(ctx context.Context) (map[string]int, error) {
+		close(started)
+		<-ctx.Done()
+		return nil, ctx.Err()
+	}, 0, cache.WithLogLevel[int](cache.LogSilent))
+	g := cache.NewGroup(0)
+	g.Add("slow", c)
+	errc := make(chan error, 1)
+	go func() { errc <- g.ReloadAll(ctx) }()
+	<-started
+	cancel()
+	if err := <-errc; !errors.Is(err, context.Canceled) {
+		t.Fatalf("ReloadAll = %v, want context.Canceled", err)
+	}
+	if c.Health().Loaded {
+		t.Fatal("cancelled load installed data")
+	}
+}
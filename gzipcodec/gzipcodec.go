@@ -0,0 +1,49 @@
+// Package gzipcodec provides a gzip-based cache.Codec for use with
+// cache.WithCompression. It lives in its own package so that programs not
+// using compression do not link it in.
+package gzipcodec
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io"
+
+	"github.com/TheOrchestraX/cache"
+)
+
+// Codec compresses values with gzip at a fixed level.
+type Codec struct {
+	level int
+}
+
+var _ cache.Codec = Codec{}
+
+// New returns a Codec using the given gzip level, such as
+// gzip.BestSpeed or gzip.DefaultCompression.
+func New(level int) (Codec, error) {
+	if _, err := gzip.NewWriterLevel(io.Discard, level); err != nil {
+		return Codec{}, err
+	}
+	return Codec{level: level}, nil
+}
+
+// Encode compresses src.
+func (c Codec) Encode(src []byte) []byte {
+	var buf bytes.Buffer
+	// The level was validated by New, and writes to a bytes.Buffer
+	// cannot fail.
+	w, _ := gzip.NewWriterLevel(&buf, c.level)
+	w.Write(src)
+	w.Close()
+	return buf.Bytes()
+}
+
+// Decode decompresses src.
+func (c Codec) Decode(src []byte) ([]byte, error) {
+	r, err := gzip.NewReader(bytes.NewReader(src))
+	if err != nil {
+		return nil, err
+	}
+	defer r.Close()
+	return io.ReadAll(r)
+}
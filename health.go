@@ -0,0 +1,63 @@
+package cache
+
+import "time"
+
+// HealthStatus summarizes whether a cache is usable, for readiness probes
+// and dashboards.
+type HealthStatus struct {
+	// Loaded reports whether a load has ever succeeded.
+	Loaded bool
+	// LastLoaded is the time of the last successful load, zero if none.
+	LastLoaded time.Time
+	// SinceLastLoad is the time elapsed since LastLoaded, zero if none.
+	SinceLastLoad time.Duration
+	// ConsecutiveFailures counts failed loads since the last success.
+	ConsecutiveFailures int
+	// LastError is the error of the most recent failed load, cleared by
+	// the next success.
+	LastError error
+	// AutoReloading reports whether periodic reloads are running; see
+	// IsAutoReloading.
+	AutoReloading bool
+	// Frozen reports whether Freeze has made the cache read-only.
+	Frozen bool
+	// Suspended reports whether auto-reload is suspended after a Fatal
+	// load error.
+	Suspended bool
+	// Items is the number of stored items, including any not yet swept
+	// after expiry.
+	Items int
+	// Freshness classifies the data's age against WithStaleThreshold and
+	// WithMaxDataAge. Expired data is reported as such whether or not the
+	// loader is currently failing.
+	Freshness Freshness
+}
+
+// Health returns a snapshot of the cache's health. It only reads a few
+// fields under the read lock, so it is cheap enough to call every second.
+func (c *Cache[T]) Health() HealthStatus {
+	now := c.clock.Now()
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	h := HealthStatus{
+		Loaded:              !c.lastLoaded.IsZero(),
+		LastLoaded:          c.lastLoaded,
+		ConsecutiveFailures: c.failures,
+		LastError:           c.lastErr,
+		AutoReloading:       c.autoReloadingLocked(),
+		Frozen:              c.frozen.Load(),
+		Suspended:           c.suspended,
+		Items:               len(c.data),
+		Freshness:           c.freshnessLocked(now),
+	}
+	if h.Loaded {
+		h.SinceLastLoad = now.Sub(c.lastLoaded)
+	}
+	return h
+}
+
+// Healthy reports whether a load has succeeded within maxStaleness.
+func (c *Cache[T]) Healthy(maxStaleness time.Duration) bool {
+	h := c.Health()
+	return h.Loaded && h.SinceLastLoad <= maxStaleness
+}
@@ -0,0 +1,58 @@
+package cache_test
+
+import (
+	"maps"
+	"testing"
+	"time"
+
+	"github.com/TheOrchestraX/cache"
+	"github.com/TheOrchestraX/cache/cachetest"
+)
+
+// epoch is the starting time of every test's fake clock.
+var epoch = time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+// newTestCache returns a silent, manual-only cache on a fake clock whose
+// loader returns a copy of data, loaded once.
+func newTestCache[T any](t *testing.T, data map[string]T, opts ...cache.Option[T]) (*cache.Cache[T], *cachetest.FakeClock) {
+	t.Helper()
+	clk := cachetest.NewFakeClock(epoch)
+	opts = append([]cache.Option[T]{cache.WithClock[T](clk), cache.WithLogLevel[T](cache.LogSilent)}, opts...)
+	c := cache.NewCache(func() (map[string]T, error) { return maps.Clone(data), nil }, 0, opts...)
+	if err := c.Load(); err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	return c, clk
+}
+
+// mustGet returns the value for key, failing the test if it is absent.
+func mustGet[T any](t *testing.T, c *cache.Cache[T], key string) T {
+	t.Helper()
+	v, ok := c.Get(key)
+	if !ok {
+		t.Fatalf("Get(%q): missing", key)
+	}
+	return v
+}
+
+// mustMiss fails the test if key is present.
+func mustMiss[T any](t *testing.T, c *cache.Cache[T], key string) {
+	t.Helper()
+	if v, ok := c.Get(key); ok {
+		t.Fatalf("Get(%q) = %v, want missing", key, v)
+	}
+}
+
+// waitFor polls cond until it holds, failing the test after five seconds.
+// It is for conditions reached by a cache goroutine, such as a flush or
+// reload goroutine arming its timer on a fake clock.
+func waitFor(t *testing.T, what string, cond func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatalf("timed out waiting for %s", what)
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
@@ -0,0 +1,111 @@
+package cache
+
+import (
+	"fmt"
+	"slices"
+	"time"
+)
+
+// historyRecord is the contents installed by one load, kept for GetAt and
+// GetFrom.
+type historyRecord[T any] struct {
+	gen  uint64    // Generation once the load was installed
+	at   time.Time // when it was installed
+	data map[string]*entry[T]
+}
+
+// WithHistoryDepth keeps the contents installed by the last n loads, for
+// GetAt and GetFrom to answer what was served for a key at an earlier
+// point. The maps are shared with the cache rather than copied, but the
+// first write after each load then copies the live map, and the retained
+// maps keep their items in memory until they age out. A n <= 0 keeps no
+// history.
+func WithHistoryDepth[T any](n int) Option[T] {
+	return func(c *Cache[T]) {
+		c.historyDepth = max(n, 0)
+	}
+}
+
+// recordHistoryLocked keeps the freshly loaded contents, dropping the
+// oldest record beyond the depth. The caller must hold c.mu for writing.
+func (c *Cache[T]) recordHistoryLocked() {
+	if c.historyDepth == 0 {
+		return
+	}
+	c.shared.Store(true)
+	rec := historyRecord[T]{gen: c.generation.Load(), at: c.clock.Now(), data: c.data}
+	c.history = append(c.history, rec)
+	if n := len(c.history) - c.historyDepth; n > 0 {
+		clear(c.history[:n])
+		c.history = c.history[n:]
+	}
+}
+
+// GetAt returns the value key had in the newest retained load installed at
+// or before generation, as reported by Generation. History only records
+// loads, so writes made between two loads are not visible through it. A
+// generation older than the oldest retained load returns an error wrapping
+// ErrHistoryEvicted rather than a miss. Lookups count towards no
+// statistics. An alias resolves to the item that claimed it in that load,
+// which takes a scan of the load's items.
+func (c *Cache[T]) GetAt(key string, generation uint64) (T, bool, error) {
+	key = c.normKey(key)
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	for i := len(c.history) - 1; i >= 0; i-- {
+		if rec := c.history[i]; rec.gen <= generation {
+			v, ok := c.historyGet(rec, key, rec.at)
+			return v, ok, nil
+		}
+	}
+	var zero T
+	return zero, false, fmt.Errorf("%w: generation %d", ErrHistoryEvicted, generation)
+}
+
+// GetFrom is GetAt for a point in time: it returns the value key had in
+// the newest retained load installed at or before t, treating items that
+// had expired by t as absent.
+func (c *Cache[T]) GetFrom(key string, t time.Time) (T, bool, error) {
+	key = c.normKey(key)
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	for i := len(c.history) - 1; i >= 0; i-- {
+		if rec := c.history[i]; !rec.at.After(t) {
+			v, ok := c.historyGet(rec, key, t)
+			return v, ok, nil
+		}
+	}
+	var zero T
+	return zero, false, fmt.Errorf("%w: %s", ErrHistoryEvicted, t.Format(time.RFC3339))
+}
+
+// historyGet looks key up in rec, as of now. The caller must hold c.mu.
+func (c *Cache[T]) historyGet(rec historyRecord[T], key string, now time.Time) (T, bool) {
+	e, ok := rec.data[c.historyResolve(rec, key)]
+	if !ok || e.expired(now) {
+		var zero T
+		return zero, false
+	}
+	return c.output(e.val()), true
+}
+
+// historyResolve is resolveLocked against the items of rec rather than the
+// current alias index, which only describes the live contents.
+func (c *Cache[T]) historyResolve(rec historyRecord[T], key string) string {
+	if c.aliases == nil {
+		return key
+	}
+	if _, ok := rec.data[key]; ok {
+		return key
+	}
+	primary, found := key, false
+	for k, e := range rec.data {
+		if found && k >= primary {
+			continue
+		}
+		if slices.Contains(c.aliases.keysFn(e.val()), key) {
+			primary, found = k, true
+		}
+	}
+	return primary
+}
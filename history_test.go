@@ -0,0 +1,55 @@
+package cache_test
+
+import (
+	"maps"
+	"testing"
+	"time"
+
+	"github.com/TheOrchestraX/cache"
+	"github.com/TheOrchestraX/cache/cachetest"
+)
+
+func TestHistoryResolvesAliasPerLoad(t *testing.T) {
+	clk := cachetest.NewFakeClock(epoch)
+	data := map[string]string{"u1": "alice@example.com"}
+	c := cache.NewCache(func() (map[string]string, error) { return maps.Clone(data), nil }, 0,
+		cache.WithClock[string](clk),
+		cache.WithHistoryDepth[string](4),
+		cache.WithAliases(byEmail))
+	if err := c.Load(); err != nil {
+		t.Fatal(err)
+	}
+	gen1, at1 := c.Generation(), clk.Now()
+
+	// The alias moves to another item in the next load.
+	clk.Advance(time.Minute)
+	data = map[string]string{"u1": "alice@example.org", "u2": "alice@example.com"}
+	if err := c.Load(); err != nil {
+		t.Fatal(err)
+	}
+	gen2, at2 := c.Generation(), clk.Now()
+
+	for _, tc := range []struct {
+		name string
+		get  func() (string, bool, error)
+		want string
+	}{
+		{"GetAt first load", func() (string, bool, error) { return c.GetAt("alice@example.com", gen1) }, "alice@example.com"},
+		{"GetAt second load", func() (string, bool, error) { return c.GetAt("alice@example.org", gen2) }, "alice@example.org"},
+		{"GetFrom first load", func() (string, bool, error) { return c.GetFrom("alice@example.com", at1) }, "alice@example.com"},
+		{"GetFrom second load", func() (string, bool, error) { return c.GetFrom("alice@example.org", at2) }, "alice@example.org"},
+	} {
+		v, ok, err := tc.get()
+		if err != nil || !ok || v != tc.want {
+			t.Errorf("%s = %q, %v, %v; want %q", tc.name, v, ok, err, tc.want)
+		}
+	}
+
+	// The first load knew nothing of alice@example.org.
+	if v, ok, err := c.GetAt("alice@example.org", gen1); err != nil || ok {
+		t.Errorf("GetAt(new alias, gen1) = %q, %v, %v; want a miss", v, ok, err)
+	}
+	if v, ok, err := c.GetFrom("alice@example.org", at1); err != nil || ok {
+		t.Errorf("GetFrom(new alias, at1) = %q, %v, %v; want a miss", v, ok, err)
+	}
+}
@@ -0,0 +1,200 @@
+package cache
+
+import "time"
+
+// hooks holds the registered mutation callbacks. Registration appends under
+// c.mu, so a copy taken under the lock can be fired after releasing it.
+type hooks[T any] struct {
+	onAdd    []func(key string, value T)
+	onChange []func(key string, old T, oldExists bool, new T)
+	onDelete []func(key string)
+	onEvict  []func(key string, value T)
+
+	onLoadStart  []func()
+	onLoadError  []func(err error, consecutiveFailures int)
+	onSlowReload []func(took, average time.Duration)
+
+	onShadowReport []func(ShadowReport)
+	onReload       []func(items int)
+	onSizeChange   []func(n int)
+}
+
+// mutation records a change applied under the lock so that hooks can be
+// fired once the lock has been released.
+type mutation[T any] struct {
+	key     string
+	value   T
+	deleted bool
+	evicted bool // deleted by WithMaxEntries; value holds the evicted value
+
+	// old is the live value replaced by a write, if oldExists. It is only
+	// filled in when OnChange hooks are registered.
+	old       T
+	oldExists bool
+}
+
+// OnAdd registers fn to be called after an item is added or updated via
+// Add, AddWithTTL or a committed Txn. Reloads do not trigger it. fn runs
+// outside the cache lock and may call back into the cache.
+func (c *Cache[T]) OnAdd(fn func(key string, value T)) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.hooks.onAdd = append(c.hooks.onAdd, fn)
+}
+
+// OnChange registers fn to be called after an item is added or updated,
+// with the value it replaced: oldExists is false for a new key. Writes
+// trigger it like OnAdd. With WithEqual set, loads trigger it too, for
+// every key they add and every value they change; without it they do not,
+// as changed values cannot be told apart. old and new are the values a Get
+// would return, so with WithCloner they are copies. fn runs outside the
+// cache lock.
+func (c *Cache[T]) OnChange(fn func(key string, old T, oldExists bool, new T)) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.hooks.onChange = append(c.hooks.onChange, fn)
+}
+
+// OnDelete registers fn to be called after an item is removed via Delete,
+// DeleteByPrefix or a committed Txn. Reloads, Clear and expiry do not
+// trigger it. fn runs outside the cache lock.
+func (c *Cache[T]) OnDelete(fn func(key string)) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.hooks.onDelete = append(c.hooks.onDelete, fn)
+}
+
+// OnLoadStart registers fn to be called before each load invokes the
+// loader, once per Load call. Every such call is matched by exactly one
+// end event: OnReload if the load succeeds, OnLoadError otherwise, so the
+// pair can bracket the loader's work, for example to shed other load on a
+// shared connection pool. SetAll and the other ways of installing data
+// without a loader fire the end events alone. fn runs outside the cache
+// lock on the loading goroutine.
+func (c *Cache[T]) OnLoadStart(fn func()) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.hooks.onLoadStart = append(c.hooks.onLoadStart, fn)
+}
+
+// loadStarted fires the OnLoadStart hooks.
+func (c *Cache[T]) loadStarted() {
+	c.mu.RLock()
+	h := c.hooks
+	c.mu.RUnlock()
+	for _, fn := range h.onLoadStart {
+		fn()
+	}
+}
+
+// OnLoadError registers fn to be called after every failed load, including
+// loader panics, with the number of consecutive failures so far (reset by
+// the next successful load). It is also called for loads abandoned because
+// their context was cancelled or the cache was frozen meanwhile, with that
+// error; these do not count as failures, so the count is unchanged. fn runs
+// outside the cache lock on the loading goroutine, so it may call Get and
+// other methods freely. IsFatal tells a Fatal error, which suspends
+// auto-reload, from a transient one.
+func (c *Cache[T]) OnLoadError(fn func(err error, consecutiveFailures int)) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.hooks.onLoadError = append(c.hooks.onLoadError, fn)
+}
+
+// OnReload registers fn to be called after every successful load, and
+// after SetAll, with the number of items installed. fn runs outside the
+// cache lock, after any OnEvict and OnSizeChange hooks.
+func (c *Cache[T]) OnReload(fn func(items int)) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.hooks.onReload = append(c.hooks.onReload, fn)
+}
+
+// notify fires the hooks in h for each of the mutations, in order, then
+// reports any resulting size change. Mutations mirrored by WithWriteBehind
+// must already have been staged with writeBehindStageLocked, which notify
+// waits on. It must be called without holding c.mu.
+func (c *Cache[T]) notify(h hooks[T], muts []mutation[T]) {
+	c.writeBehindWait()
+	for _, m := range muts {
+		if m.evicted {
+			for _, fn := range h.onEvict {
+				fn(m.key, m.value)
+			}
+			continue
+		}
+		if m.deleted {
+			for _, fn := range h.onDelete {
+				fn(m.key)
+			}
+			continue
+		}
+		for _, fn := range h.onAdd {
+			fn(m.key, m.value)
+		}
+		for _, fn := range h.onChange {
+			fn(m.key, m.old, m.oldExists, m.value)
+		}
+	}
+	c.sizeChanged()
+}
+
+// notifyChanges fires the OnChange hooks in h for changes made by a load.
+// It must be called without holding c.mu.
+func (c *Cache[T]) notifyChanges(h hooks[T], changes []mutation[T]) {
+	for _, m := range changes {
+		for _, fn := range h.onChange {
+			fn(m.key, m.old, m.oldExists, m.value)
+		}
+	}
+}
+
+// setNotifiedLocked is setLocked for a write that fires hooks, returning
+// its mutation along with the live value it replaced if OnChange hooks
+// need it. The caller must hold c.mu for writing.
+func (c *Cache[T]) setNotifiedLocked(key string, value T, e *entry[T], now time.Time) mutation[T] {
+	m := mutation[T]{key: key, value: value}
+	if len(c.hooks.onChange) > 0 {
+		if old, ok := c.data[key]; ok && !old.expired(now) {
+			m.old, m.oldExists = c.output(old.val()), true
+		}
+	}
+	c.setLocked(key, e)
+	return m
+}
+
+// watchesLoadsLocked reports whether loads must report their changes to
+// OnChange hooks, which needs WithEqual to tell changed values apart.
+func (c *Cache[T]) watchesLoadsLocked() bool {
+	return c.equal != nil && len(c.hooks.onChange) > 0
+}
+
+// loadChangeLocked appends to changes the OnChange notification for a load
+// storing e under key in place of prev, which may be nil: none unless
+// watchesLoadsLocked, the key is new or the value changed.
+func (c *Cache[T]) loadChangeLocked(changes []mutation[T], key string, prev, e *entry[T], now time.Time) []mutation[T] {
+	if prev == e || !c.watchesLoadsLocked() {
+		return changes
+	}
+	m := mutation[T]{key: key, value: c.output(e.val())}
+	if prev != nil && !prev.expired(now) {
+		if c.equal(prev.val(), e.val()) {
+			return changes
+		}
+		m.old, m.oldExists = c.output(prev.val()), true
+	}
+	return append(changes, m)
+}
+
+// loadAbandoned notifies OnLoadError hooks of a load that ended with err
+// without failing, such as a cancelled one, and returns err.
+func (c *Cache[T]) loadAbandoned(err error) error {
+	c.mu.RLock()
+	failures := c.failures
+	h := c.hooks
+	c.mu.RUnlock()
+	for _, fn := range h.onLoadError {
+		fn(err, failures)
+	}
+	return err
+}
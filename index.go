@@ -0,0 +1,90 @@
+package cache
+
+// index maps derived index keys to the primary keys of the items that
+// produce them. Several primary keys may share one index key.
+type index[T any] struct {
+	keysFn func(T) []string
+	byKey  map[string]map[string]struct{}
+}
+
+// newIndex builds an index over data using keysFn.
+func newIndex[T any](keysFn func(T) []string, data map[string]*entry[T]) *index[T] {
+	ix := &index[T]{keysFn: keysFn}
+	ix.rebuild(data)
+	return ix
+}
+
+// add records primary under every index key derived from v.
+func (ix *index[T]) add(primary string, v T) {
+	for _, k := range ix.keysFn(v) {
+		set, ok := ix.byKey[k]
+		if !ok {
+			set = make(map[string]struct{})
+			ix.byKey[k] = set
+		}
+		set[primary] = struct{}{}
+	}
+}
+
+// remove drops primary from every index key derived from v.
+func (ix *index[T]) remove(primary string, v T) {
+	for _, k := range ix.keysFn(v) {
+		set, ok := ix.byKey[k]
+		if !ok {
+			continue
+		}
+		delete(set, primary)
+		if len(set) == 0 {
+			delete(ix.byKey, k)
+		}
+	}
+}
+
+// rebuild discards the index contents and recomputes them from data.
+func (ix *index[T]) rebuild(data map[string]*entry[T]) {
+	ix.byKey = make(map[string]map[string]struct{})
+	for k, e := range data {
+		ix.add(k, e.val())
+	}
+}
+
+// AddIndex registers a secondary index named name, mapping keyFn(value) to
+// the keys of all items producing it. The index is built immediately,
+// rebuilt on every Load, and updated on Add and Delete. Registering an
+// existing name replaces that index.
+func (c *Cache[T]) AddIndex(name string, keyFn func(T) string) {
+	c.AddMultiIndex(name, func(v T) []string { return []string{keyFn(v)} })
+}
+
+// AddMultiIndex registers a secondary index in which each item may appear
+// under several index keys, such as every group a user belongs to. When an
+// Add changes an item's index keys, it is removed from the keys it no longer
+// produces and added to the new ones. Maintenance follows AddIndex.
+func (c *Cache[T]) AddMultiIndex(name string, keysFn func(T) []string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.indexes == nil {
+		c.indexes = make(map[string]*index[T])
+	}
+	c.indexes[name] = newIndex(keysFn, c.data)
+}
+
+// GetByIndex returns all live items whose index key in the named index
+// equals indexKey. It returns nil for unknown indexes or keys.
+func (c *Cache[T]) GetByIndex(name, indexKey string) []T {
+	now := c.clock.Now()
+	c.clearExpiredData(now)
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	ix, ok := c.indexes[name]
+	if !ok {
+		return nil
+	}
+	var results []T
+	for k := range ix.byKey[indexKey] {
+		if e := c.data[k]; !e.expired(now) {
+			results = append(results, c.output(e.val()))
+		}
+	}
+	return results
+}
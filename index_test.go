@@ -0,0 +1,74 @@
+package cache_test
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/TheOrchestraX/cache"
+)
+
+type member struct {
+	Name   string
+	Groups []string
+}
+
+// names returns the sorted names of members.
+func names(ms []member) []string {
+	var out []string
+	for _, m := range ms {
+		out = append(out, m.Name)
+	}
+	slices.Sort(out)
+	return out
+}
+
+func TestMultiIndexFollowsUpdates(t *testing.T) {
+	c, _ := newTestCache(t, map[string]member{
+		"alice": {Name: "alice", Groups: []string{"admins", "ops"}},
+		"bob":   {Name: "bob", Groups: []string{"ops"}},
+	})
+	c.AddMultiIndex("group", func(m member) []string { return m.Groups })
+	if got := names(c.GetByIndex("group", "ops")); !slices.Equal(got, []string{"alice", "bob"}) {
+		t.Fatalf("ops = %v, want alice and bob", got)
+	}
+
+	// alice leaves ops, stays in admins and joins dev.
+	c.Add("alice", member{Name: "alice", Groups: []string{"admins", "dev"}})
+	for group, want := range map[string][]string{
+		"ops":    {"bob"},
+		"admins": {"alice"},
+		"dev":    {"alice"},
+	} {
+		if got := names(c.GetByIndex("group", group)); !slices.Equal(got, want) {
+			t.Fatalf("%s = %v, want %v", group, got, want)
+		}
+	}
+
+	c.Delete("bob")
+	if got := c.GetByIndex("group", "ops"); got != nil {
+		t.Fatalf("ops after deleting bob = %v, want nil", got)
+	}
+	if got := c.GetByIndex("missing", "ops"); got != nil {
+		t.Fatalf("unknown index = %v, want nil", got)
+	}
+}
+
+func TestIndexRebuiltOnLoad(t *testing.T) {
+	data := map[string]member{"alice": {Name: "alice", Groups: []string{"ops"}}}
+	c := cache.NewCache(func() (map[string]member, error) { return data, nil }, 0,
+		cache.WithLogLevel[member](cache.LogSilent))
+	c.AddIndex("first", func(m member) string { return m.Groups[0] })
+	if err := c.Load(); err != nil {
+		t.Fatal(err)
+	}
+	data = map[string]member{"alice": {Name: "alice", Groups: []string{"dev"}}}
+	if err := c.Load(); err != nil {
+		t.Fatal(err)
+	}
+	if got := c.GetByIndex("first", "ops"); got != nil {
+		t.Fatalf("ops after reload = %v, want nil", got)
+	}
+	if got := names(c.GetByIndex("first", "dev")); !slices.Equal(got, []string{"alice"}) {
+		t.Fatalf("dev after reload = %v, want alice", got)
+	}
+}
@@ -0,0 +1,23 @@
+package cache
+
+// Inspector is the non-generic read surface of a cache, for debug tooling
+// and admin UIs that list caches of different value types side by side.
+// *Cache[T] implements it for every T.
+type Inspector interface {
+	Name() string
+	Len() int
+	Keys() []string
+	GetAny(key string) (any, bool)
+}
+
+var _ Inspector = (*Cache[int])(nil)
+
+// GetAny is Get with the value boxed in an interface, for callers that do
+// not know T. It returns nil, false for missing keys.
+func (c *Cache[T]) GetAny(key string) (any, bool) {
+	v, ok := c.Get(key)
+	if !ok {
+		return nil, false
+	}
+	return v, true
+}
@@ -0,0 +1,82 @@
+package cache
+
+import "time"
+
+// janitorRun is the state owned by one janitor goroutine, fresh for each
+// start like reloadRun.
+type janitorRun struct {
+	stop chan struct{} // closed by stopJanitorLocked
+	done chan struct{} // closed once the goroutine has exited
+}
+
+// WithJanitorInterval makes the cache sweep out expired items every d, as
+// DeleteExpired does, while it runs. The janitor starts and stops with
+// auto-reload; caches that do not auto-reload can run it alone with
+// StartJanitor. A d <= 0 disables it.
+func WithJanitorInterval[T any](d time.Duration) Option[T] {
+	return func(c *Cache[T]) {
+		c.janitorInterval = d
+	}
+}
+
+// StartJanitor starts only the janitor configured by WithJanitorInterval,
+// for caches using TTLs without auto-reload. StopAutoReload and StopAndWait
+// stop it. It does nothing if the janitor is already running, and logs and
+// does nothing without a janitor interval.
+func (c *Cache[T]) StartJanitor() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.janitorInterval <= 0 {
+		c.logf(LogInfo, "%s has no janitor interval; not starting the janitor", c.label())
+		return
+	}
+	c.startJanitorLocked()
+}
+
+// startJanitorLocked starts the janitor goroutine if an interval is set
+// and it is not running. The caller must hold c.mu for writing.
+func (c *Cache[T]) startJanitorLocked() {
+	if c.janitor != nil || c.janitorInterval <= 0 {
+		return
+	}
+	run := &janitorRun{stop: make(chan struct{}), done: make(chan struct{})}
+	prev := c.lastJanitor
+	c.janitor, c.lastJanitor = run, run
+	go c.janitorLoop(run, c.clock.NewTicker(c.janitorInterval), prev)
+}
+
+// stopJanitorLocked stops the janitor goroutine, if running. The caller
+// must hold c.mu for writing.
+func (c *Cache[T]) stopJanitorLocked() {
+	if c.janitor == nil {
+		return
+	}
+	close(c.janitor.stop)
+	c.janitor = nil
+}
+
+// janitorLoop is the janitor goroutine for run. Like reloadLoop it first
+// waits for its predecessor to exit.
+func (c *Cache[T]) janitorLoop(run *janitorRun, ticker Ticker, prev *janitorRun) {
+	defer close(run.done)
+	defer ticker.Stop()
+	if prev != nil {
+		<-prev.done
+	}
+	for {
+		select {
+		case <-ticker.C():
+			select {
+			case <-run.stop:
+				return
+			default:
+			}
+			c.clearExpiredData(c.clock.Now())
+			if n := c.DeleteExpired(); n > 0 {
+				c.logf(LogDebug, "%s janitor removed %d expired items", c.label(), n)
+			}
+		case <-run.stop:
+			return
+		}
+	}
+}
@@ -0,0 +1,121 @@
+package cache
+
+import "fmt"
+
+// ChangeOp is the kind of a journaled change.
+type ChangeOp uint8
+
+const (
+	// ChangeSet means the key was added or its value changed.
+	ChangeSet ChangeOp = iota
+	// ChangeDelete means the key was removed.
+	ChangeDelete
+)
+
+// String returns the operation's name.
+func (op ChangeOp) String() string {
+	switch op {
+	case ChangeSet:
+		return "set"
+	case ChangeDelete:
+		return "delete"
+	}
+	return fmt.Sprintf("ChangeOp(%d)", int(op))
+}
+
+// Change is one journaled change to a key. Seq numbers increase by one per
+// change, starting at 1.
+type Change struct {
+	Seq uint64
+	Op  ChangeOp
+	Key string
+}
+
+// journal is a ring of the most recent changes, guarded by Cache.mu.
+type journal struct {
+	ring []Change
+	next int    // index the next change is written to
+	seq  uint64 // Seq of the most recent change
+}
+
+// record appends a change, overwriting the oldest once the ring is full.
+func (j *journal) record(op ChangeOp, key string) {
+	j.seq++
+	j.ring[j.next] = Change{Seq: j.seq, Op: op, Key: key}
+	j.next = (j.next + 1) % len(j.ring)
+}
+
+// oldest returns the Seq of the oldest retained change, or seq+1 if the
+// ring is empty.
+func (j *journal) oldest() uint64 {
+	if j.seq < uint64(len(j.ring)) {
+		return 1
+	}
+	return j.seq - uint64(len(j.ring)) + 1
+}
+
+// WithJournal records the last capacity changes to keys, so that consumers
+// syncing the cache into their own stores can fetch only what changed with
+// DeltaSince. Writes and deletes are journaled as they happen; reloads
+// journal the keys they add, remove or change. Without WithEqual every
+// reloaded key counts as changed, so set it to keep reloads from flooding
+// the journal. Expired items are journaled as deletes when swept.
+func WithJournal[T any](capacity int) Option[T] {
+	return func(c *Cache[T]) {
+		if capacity <= 0 {
+			c.journal = nil
+			return
+		}
+		c.journal = &journal{ring: make([]Change, capacity)}
+	}
+}
+
+// DeltaSince returns the changes after seq, oldest first, and the Seq of
+// the latest change, to pass to the next call. Start from 0. ok is false if
+// changes after seq have already been dropped from the journal, or no
+// journal is configured; the caller must then resync in full, e.g. from
+// GetAll, and continue from the returned currentSeq.
+func (c *Cache[T]) DeltaSince(seq uint64) (changes []Change, currentSeq uint64, ok bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	j := c.journal
+	if j == nil {
+		return nil, 0, false
+	}
+	if seq > j.seq || seq+1 < j.oldest() {
+		return nil, j.seq, false
+	}
+	n := int(j.seq - seq)
+	changes = make([]Change, n)
+	start := (j.next - n + len(j.ring)) % len(j.ring)
+	for i := range changes {
+		changes[i] = j.ring[(start+i)%len(j.ring)]
+	}
+	return changes, j.seq, true
+}
+
+// journalSetLocked journals the storing of e under key, replacing old (nil
+// if the key is new), unless a reload left the value unchanged. The caller
+// must hold c.mu for writing.
+func (c *Cache[T]) journalSetLocked(key string, old, e *entry[T]) {
+	if c.journal != nil && (old == nil || old.version != e.version) {
+		c.journal.record(ChangeSet, key)
+	}
+}
+
+// journalReplaceLocked journals the differences between the contents and
+// data, which is about to replace them. The caller must hold c.mu for
+// writing.
+func (c *Cache[T]) journalReplaceLocked(data map[string]*entry[T]) {
+	if c.journal == nil {
+		return
+	}
+	for k, e := range data {
+		c.journalSetLocked(k, c.data[k], e)
+	}
+	for k := range c.data {
+		if _, ok := data[k]; !ok {
+			c.journal.record(ChangeDelete, k)
+		}
+	}
+}
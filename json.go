@@ -0,0 +1,61 @@
+package cache
+
+import "encoding/json"
+
+// MarshalJSON encodes the cache's live items as a JSON object keyed by
+// item key. Configuration such as the loader and reload interval is not
+// included. A zero Cache encodes as an empty object.
+func (c *Cache[T]) MarshalJSON() ([]byte, error) {
+	if c.clock == nil {
+		return []byte("{}"), nil
+	}
+	return json.Marshal(c.GetAll())
+}
+
+// UnmarshalJSON replaces the cache's contents with the items of a JSON
+// object, in one atomic swap. Configuration and any running auto-reload are
+// left alone, so the next reload replaces the decoded items as usual.
+// Items get the TTL a load would give them and are validated as a load's
+// would be. On a decoding or validation error the contents are unchanged,
+// and while the cache is frozen it returns ErrFrozen.
+//
+// A zero Cache, such as json.Unmarshal allocates for a nil *Cache field, is
+// first given the defaults of NewCache(nil, 0): it holds the decoded items
+// and never reloads.
+func (c *Cache[T]) UnmarshalJSON(b []byte) error {
+	c.initZero()
+	if c.rejectFrozen("import") {
+		return ErrFrozen
+	}
+	var items map[string]T
+	if err := json.Unmarshal(b, &items); err != nil {
+		return err
+	}
+	items, err := c.validItems(items)
+	if err != nil {
+		return err
+	}
+	data := c.loadEntries(items)
+	c.mu.Lock()
+	c.stripTombstonedLocked(data)
+	c.replaceLocked(data)
+	evicted := c.evictLocked()
+	h := c.hooks
+	c.mu.Unlock()
+	c.notify(h, evicted)
+	return nil
+}
+
+// initZero sets up a zero Cache the way newCache does without options. It
+// leaves a constructed cache alone.
+func (c *Cache[T]) initZero() {
+	if c.clock != nil {
+		return
+	}
+	c.data = make(map[string]*entry[T])
+	c.minDelay = defaultMinReloadDelay
+	c.clock = realClock{}
+	c.logLevel = LogInfo
+	c.id = cacheIDs.Add(1)
+	c.statsSince = c.clock.Now()
+}
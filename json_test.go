@@ -0,0 +1,62 @@
+package cache_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/TheOrchestraX/cache"
+)
+
+func TestJSONRoundTrip(t *testing.T) {
+	src, _ := newTestCache(t, map[string]int{"a": 1, "b": 2})
+	b, err := json.Marshal(src)
+	if err != nil {
+		t.Fatal(err)
+	}
+	dst, _ := newTestCache(t, map[string]int{"old": 9})
+	if err := json.Unmarshal(b, dst); err != nil {
+		t.Fatal(err)
+	}
+	mustMiss(t, dst, "old")
+	if v := mustGet(t, dst, "b"); v != 2 {
+		t.Fatalf("Get(b) = %d, want 2", v)
+	}
+}
+
+func TestJSONIntoNilCacheField(t *testing.T) {
+	var doc struct {
+		C *cache.Cache[int] `json:"c"`
+	}
+	if err := json.Unmarshal([]byte(`{"c":{"a":1,"b":2}}`), &doc); err != nil {
+		t.Fatal(err)
+	}
+	if doc.C == nil {
+		t.Fatal("C was not allocated")
+	}
+	if v := mustGet(t, doc.C, "a"); v != 1 {
+		t.Fatalf("Get(a) = %d, want 1", v)
+	}
+	doc.C.Add("c", 3)
+	if n := doc.C.Len(); n != 3 {
+		t.Fatalf("Len = %d, want 3", n)
+	}
+
+	b, err := json.Marshal(doc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := string(b), `{"c":{"a":1,"b":2,"c":3}}`; got != want {
+		t.Fatalf("Marshal = %s, want %s", got, want)
+	}
+}
+
+func TestJSONMarshalZeroCache(t *testing.T) {
+	var c cache.Cache[int]
+	b, err := json.Marshal(&c)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := string(b); got != "{}" {
+		t.Fatalf("Marshal of a zero Cache = %s, want {}", got)
+	}
+}
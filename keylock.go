@@ -0,0 +1,55 @@
+package cache
+
+import (
+	"hash/maphash"
+	"sync"
+)
+
+// keyLockStripes is the number of mutexes backing per-key locking. Distinct
+// keys may share a stripe, so unrelated keys occasionally serialize.
+const keyLockStripes = 256
+
+// keyLockSeed is shared by all caches so stripe selection is stable.
+var keyLockSeed = maphash.MakeSeed()
+
+// keyLock returns the stripe mutex guarding key.
+func (c *Cache[T]) keyLock(key string) *sync.Mutex {
+	return &c.keyLocks[maphash.String(keyLockSeed, c.normKey(key))%keyLockStripes]
+}
+
+// LockKey acquires the per-key lock for key and returns the function that
+// releases it. Per-key locks are independent of the data lock, so holding
+// one never blocks reads or writes of the cache itself.
+func (c *Cache[T]) LockKey(key string) (unlock func()) {
+	mu := c.keyLock(key)
+	mu.Lock()
+	return mu.Unlock
+}
+
+// WithKeyLock runs fn while holding the per-key lock for key, serializing
+// it with other WithKeyLock, LockKey and GetOrCompute calls on that key.
+func (c *Cache[T]) WithKeyLock(key string, fn func()) {
+	defer c.LockKey(key)()
+	fn()
+}
+
+// GetOrCompute returns the item for key, calling compute and storing its
+// result on a miss. Concurrent misses on the same key are serialized under
+// the per-key lock, so compute runs once. Errors are returned unstored; a
+// compute that finds nothing for key should return an error wrapping
+// ErrNotFound, so callers can test for absence as they would with GetE.
+func (c *Cache[T]) GetOrCompute(key string, compute func() (T, error)) (T, error) {
+	if v, ok := c.Get(key); ok {
+		return v, nil
+	}
+	defer c.LockKey(key)()
+	if v, ok := c.Get(key); ok {
+		return v, nil
+	}
+	v, err := compute()
+	if err != nil {
+		return v, err
+	}
+	c.Add(key, v)
+	return v, nil
+}
@@ -0,0 +1,84 @@
+package cache_test
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/TheOrchestraX/cache"
+)
+
+func TestKeyLockSerializesSameKey(t *testing.T) {
+	c, _ := newTestCache(t, map[string]int{})
+	var inside, maxInside atomic.Int32
+	var wg sync.WaitGroup
+	for range 8 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			c.WithKeyLock("k", func() {
+				n := inside.Add(1)
+				if n > maxInside.Load() {
+					maxInside.Store(n)
+				}
+				time.Sleep(time.Millisecond)
+				inside.Add(-1)
+			})
+		}()
+	}
+	wg.Wait()
+	if m := maxInside.Load(); m != 1 {
+		t.Fatalf("%d holders of one key's lock at once, want 1", m)
+	}
+}
+
+func TestKeyLockOtherKeysProceed(t *testing.T) {
+	c, _ := newTestCache(t, map[string]int{})
+	unlock := c.LockKey("held")
+	// Keys share a stripe now and then, so one of several must get
+	// through while "held" is locked.
+	done := make(chan struct{}, 8)
+	for i := range 8 {
+		go c.WithKeyLock(fmt.Sprintf("other%d", i), func() { done <- struct{}{} })
+	}
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("no other key could be locked while one was held")
+	}
+	unlock()
+	for range 7 {
+		<-done
+	}
+}
+
+func TestGetOrComputeOncePerKey(t *testing.T) {
+	c, _ := newTestCache(t, map[string]int{})
+	var calls atomic.Int32
+	var wg sync.WaitGroup
+	for range 8 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			v, err := c.GetOrCompute("k", func() (int, error) {
+				calls.Add(1)
+				time.Sleep(time.Millisecond)
+				return 42, nil
+			})
+			if err != nil || v != 42 {
+				t.Errorf("GetOrCompute = %d, %v", v, err)
+			}
+		}()
+	}
+	wg.Wait()
+	if n := calls.Load(); n != 1 {
+		t.Fatalf("compute ran %d times, want once", n)
+	}
+	if _, err := c.GetOrCompute("absent", func() (int, error) { return 0, cache.ErrNotFound }); !errors.Is(err, cache.ErrNotFound) {
+		t.Fatalf("GetOrCompute error = %v, want ErrNotFound", err)
+	}
+	mustMiss(t, c, "absent")
+}
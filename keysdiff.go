@@ -0,0 +1,101 @@
+package cache
+
+import (
+	"hash/fnv"
+	"slices"
+)
+
+// DiffKeys compares the live keys against an external key set, such as the
+// authoritative list from the system the cache mirrors, and returns the
+// keys only the cache holds and the keys only external holds, each in no
+// particular order. Values are never copied; the cache is scanned once
+// under the read lock.
+func (c *Cache[T]) DiffKeys(external []string) (onlyInCache, onlyInExternal []string) {
+	return c.DiffKeysSeq(func(yield func(string) bool) {
+		for _, k := range external {
+			if !yield(k) {
+				return
+			}
+		}
+	})
+}
+
+// DiffKeysSeq is DiffKeys for keys produced by a sequence function, which
+// has the shape of an iter.Seq[string], so the external keys need not be
+// materialized as a slice. The sequence is consumed before the read lock
+// is taken.
+func (c *Cache[T]) DiffKeysSeq(external func(yield func(string) bool)) (onlyInCache, onlyInExternal []string) {
+	ext := make(map[string]struct{})
+	external(func(k string) bool {
+		ext[c.normKey(k)] = struct{}{}
+		return true
+	})
+
+	now := c.clock.Now()
+	c.clearExpiredData(now)
+	c.mu.RLock()
+	for k, e := range c.data {
+		if e.expired(now) {
+			continue
+		}
+		if _, ok := ext[k]; ok {
+			delete(ext, k)
+		} else {
+			onlyInCache = append(onlyInCache, k)
+		}
+	}
+	c.mu.RUnlock()
+
+	for k := range ext {
+		onlyInExternal = append(onlyInExternal, k)
+	}
+	return onlyInCache, onlyInExternal
+}
+
+// KeyDigest returns an order-independent digest of the live keys, for a
+// peer to check cheaply whether its key set matches before fetching the
+// keys themselves. The digest is the sum, modulo 2^64, of the 64-bit
+// FNV-1a hash of each key, so it depends only on the key set and is the
+// same in every process and on every platform. It is computed on demand in
+// one pass under the read lock.
+func (c *Cache[T]) KeyDigest() uint64 {
+	var sum uint64
+	now := c.clock.Now()
+	c.clearExpiredData(now)
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	for k, e := range c.data {
+		if !e.expired(now) {
+			sum += KeyHash(k)
+		}
+	}
+	return sum
+}
+
+// KeysHashed returns hash of every live key, sorted ascending, as a
+// compact stand-in for the key list when comparing key sets across
+// services. A nil hash means KeyHash, the hash KeyDigest sums.
+func (c *Cache[T]) KeysHashed(hash func(string) uint64) []uint64 {
+	if hash == nil {
+		hash = KeyHash
+	}
+	now := c.clock.Now()
+	c.clearExpiredData(now)
+	c.mu.RLock()
+	hashes := make([]uint64, 0, len(c.data))
+	for k, e := range c.data {
+		if !e.expired(now) {
+			hashes = append(hashes, hash(k))
+		}
+	}
+	c.mu.RUnlock()
+	slices.Sort(hashes)
+	return hashes
+}
+
+// KeyHash is the 64-bit FNV-1a hash of key, as used by KeyDigest.
+func KeyHash(key string) uint64 {
+	h := fnv.New64a()
+	h.Write([]byte(key))
+	return h.Sum64()
+}
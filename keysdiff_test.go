@@ -0,0 +1,92 @@
+package cache_test
+
+import (
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/TheOrchestraX/cache"
+)
+
+// The digest is compared across processes and releases, so these values
+// are pinned rather than recomputed with hash/fnv.
+func TestKeyHashGolden(t *testing.T) {
+	for key, want := range map[string]uint64{
+		"":         0xcbf29ce484222325,
+		"a":        0xaf63dc4c8601ec8c,
+		"user:1":   0xf7fd9aaa75081ceb,
+		"user:2":   0xf7fd9baa75081e9e,
+		"order:42": 0x0cef508660eaa9b1,
+	} {
+		if got := cache.KeyHash(key); got != want {
+			t.Errorf("KeyHash(%q) = %#x, want %#x", key, got, want)
+		}
+	}
+}
+
+func TestKeyDigestGolden(t *testing.T) {
+	c, clk := newTestCache(t, map[string]int{"user:1": 1, "user:2": 2, "order:42": 3})
+	if got, want := c.KeyDigest(), uint64(0xfcea86db4afae53a); got != want {
+		t.Fatalf("KeyDigest = %#x, want %#x", got, want)
+	}
+
+	// Values do not contribute; only the key set does.
+	c.Add("user:1", 100)
+	if got, want := c.KeyDigest(), uint64(0xfcea86db4afae53a); got != want {
+		t.Fatalf("KeyDigest after a value change = %#x, want %#x", got, want)
+	}
+
+	// Expired keys drop out of the digest.
+	c.AddWithTTL("order:42", 3, time.Minute)
+	clk.Advance(time.Hour)
+	if got, want := c.KeyDigest(), uint64(0xeffb3654ea103b89); got != want {
+		t.Fatalf("KeyDigest after expiry = %#x, want %#x", got, want)
+	}
+
+	empty, _ := newTestCache(t, map[string]int{})
+	if got := empty.KeyDigest(); got != 0 {
+		t.Fatalf("KeyDigest of an empty cache = %#x, want 0", got)
+	}
+}
+
+func TestKeysHashedSorted(t *testing.T) {
+	c, _ := newTestCache(t, map[string]int{"user:1": 1, "user:2": 2, "order:42": 3})
+	want := []uint64{0x0cef508660eaa9b1, 0xf7fd9aaa75081ceb, 0xf7fd9baa75081e9e}
+	if got := c.KeysHashed(nil); !slices.Equal(got, want) {
+		t.Fatalf("KeysHashed(nil) = %#x, want %#x", got, want)
+	}
+	got := c.KeysHashed(func(k string) uint64 { return uint64(len(k)) })
+	if want := []uint64{6, 6, 8}; !slices.Equal(got, want) {
+		t.Fatalf("KeysHashed(len) = %v, want %v", got, want)
+	}
+}
+
+func TestDiffKeys(t *testing.T) {
+	c, _ := newTestCache(t, map[string]int{"a": 1, "b": 2, "c": 3})
+	onlyCache, onlyExt := c.DiffKeys([]string{"b", "c", "d", "e", "d"})
+	slices.Sort(onlyCache)
+	slices.Sort(onlyExt)
+	if want := []string{"a"}; !slices.Equal(onlyCache, want) {
+		t.Fatalf("onlyInCache = %v, want %v", onlyCache, want)
+	}
+	if want := []string{"d", "e"}; !slices.Equal(onlyExt, want) {
+		t.Fatalf("onlyInExternal = %v, want %v", onlyExt, want)
+	}
+
+	onlyCache, onlyExt = c.DiffKeys([]string{"c", "a", "b"})
+	if len(onlyCache) != 0 || len(onlyExt) != 0 {
+		t.Fatalf("DiffKeys of an equal set = %v, %v, want both empty", onlyCache, onlyExt)
+	}
+}
+
+func TestDiffKeysNormalizesExternal(t *testing.T) {
+	c, _ := newTestCache(t, map[string]int{"a": 1, "b": 2},
+		cache.WithKeyNormalizer[int](canonical))
+	onlyCache, onlyExt := c.DiffKeys([]string{" A", "c "})
+	if want := []string{"b"}; !slices.Equal(onlyCache, want) {
+		t.Fatalf("onlyInCache = %v, want %v", onlyCache, want)
+	}
+	if want := []string{"c"}; !slices.Equal(onlyExt, want) {
+		t.Fatalf("onlyInExternal = %v, want %v", onlyExt, want)
+	}
+}
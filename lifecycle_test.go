@@ -0,0 +1,224 @@
+package cache_test
+
+import (
+	"context"
+	"fmt"
+	"runtime"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/TheOrchestraX/cache"
+	"github.com/TheOrchestraX/cache/cachetest"
+)
+
+// checkNoLeak fails the test if the goroutine count does not settle back
+// to base.
+func checkNoLeak(t *testing.T, base int) {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		n := runtime.NumGoroutine()
+		if n <= base {
+			return
+		}
+		if time.Now().After(deadline) {
+			buf := make([]byte, 1<<16)
+			t.Fatalf("%d goroutines left, started with %d:\n%s", n, base, buf[:runtime.Stack(buf, true)])
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestLifecycleLeavesNoGoroutines(t *testing.T) {
+	const (
+		reload = 1 << iota
+		janitor
+		writeBehind
+		loadOnStart
+		all = 1<<iota - 1
+	)
+	for combo := 0; combo <= all; combo++ {
+		t.Run(fmt.Sprintf("features=%04b", combo), func(t *testing.T) {
+			base := runtime.NumGoroutine()
+			interval := time.Duration(0)
+			opts := []cache.Option[int]{cache.WithLogLevel[int](cache.LogSilent)}
+			if combo&reload != 0 {
+				interval = time.Millisecond
+			}
+			if combo&janitor != 0 {
+				opts = append(opts, cache.WithJanitorInterval[int](time.Millisecond))
+			}
+			if combo&writeBehind != 0 {
+				opts = append(opts, cache.WithWriteBehind(func(context.Context, []cache.Op[int]) error { return nil }, time.Millisecond, 10))
+			}
+			if combo&loadOnStart != 0 {
+				opts = append(opts, cache.WithLoadOnStart[int](true))
+			}
+			c := cache.NewCache(func() (map[string]int, error) { return map[string]int{"a": 1}, nil }, interval, opts...)
+			for i := range 5 {
+				c.StartAutoReload()
+				c.AddWithTTL("k", i, time.Millisecond)
+				time.Sleep(2 * time.Millisecond)
+				if i%2 == 0 {
+					c.StopAutoReload() // may leave goroutines winding down
+				} else if err := c.StopAndWait(context.Background()); err != nil {
+					t.Fatal(err)
+				}
+			}
+			if combo&janitor != 0 {
+				c.StartJanitor()
+				c.StopAutoReload()
+			}
+			if err := c.Close(context.Background()); err != nil {
+				t.Fatal(err)
+			}
+			if c.IsAutoReloading() {
+				t.Fatal("still auto-reloading after Close")
+			}
+			checkNoLeak(t, base)
+		})
+	}
+}
+
+func TestConcurrentStartStopRunsOneLoop(t *testing.T) {
+	const interval = 5 * time.Millisecond
+	var active, maxActive, loads atomic.Int32
+	c := cache.NewCache(func() (map[string]int, error) {
+		n := active.Add(1)
+		defer active.Add(-1)
+		for {
+			m := maxActive.Load()
+			if n <= m || maxActive.CompareAndSwap(m, n) {
+				break
+			}
+		}
+		loads.Add(1)
+		time.Sleep(time.Millisecond)
+		return map[string]int{"a": 1}, nil
+	}, interval, cache.WithLogLevel[int](cache.LogSilent))
+
+	base := runtime.NumGoroutine()
+	start := time.Now()
+	var wg sync.WaitGroup
+	for g := range 8 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range 300 {
+				switch (g + i) % 4 {
+				case 0, 1:
+					c.StartAutoReload()
+				case 2:
+					c.StopAutoReload()
+				case 3:
+					c.SetInterval(interval)
+				}
+				if i%50 == 0 {
+					time.Sleep(interval)
+				}
+			}
+		}()
+	}
+	wg.Wait()
+	if err := c.StopAndWait(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	elapsed := time.Since(start)
+
+	if m := maxActive.Load(); m > 1 {
+		t.Fatalf("%d loads ran at once, want at most one loop", m)
+	}
+	// Loops never overlap and each waits a full interval before loading,
+	// so loads cannot outpace the interval.
+	if n, limit := loads.Load(), int32(elapsed/interval)+1; n > limit {
+		t.Fatalf("%d loads in %s, want at most %d", n, elapsed, limit)
+	}
+	checkNoLeak(t, base)
+}
+
+func TestStopDiscardsInFlightLoad(t *testing.T) {
+	for _, tc := range []struct {
+		name string
+		// finish ends the slow load once the cache is stopped.
+		finish func(ctx context.Context) (map[string]int, error)
+	}{
+		{"ignores cancellation", func(context.Context) (map[string]int, error) {
+			return map[string]int{"a": 2}, nil
+		}},
+		{"honours cancellation", func(ctx context.Context) (map[string]int, error) {
+			<-ctx.Done()
+			return nil, ctx.Err()
+		}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			var slow atomic.Bool
+			entered, release := make(chan struct{}), make(chan struct{})
+			c := cache.NewCacheWithContext(func(ctx context.Context) (map[string]int, error) {
+				if !slow.Load() {
+					return map[string]int{"a": 1}, nil
+				}
+				close(entered)
+				<-release
+				return tc.finish(ctx)
+			}, time.Hour,
+				cache.WithClock[int](cachetest.NewFakeClock(epoch)),
+				cache.WithLogLevel[int](cache.LogSilent),
+				cache.WithLoadOnStart[int](true))
+			if err := c.Load(); err != nil {
+				t.Fatal(err)
+			}
+			slow.Store(true)
+
+			c.StartAutoReload()
+			<-entered
+			c.StopAutoReload()
+			close(release)
+			if err := c.StopAndWait(context.Background()); err != nil {
+				t.Fatal(err)
+			}
+			if v := mustGet(t, c, "a"); v != 1 {
+				t.Fatalf("Get(a) = %d after stop, want 1: the stopped load was installed", v)
+			}
+			if n := c.Stats().Loads; n != 1 {
+				t.Fatalf("Loads = %d, want 1", n)
+			}
+		})
+	}
+}
+
+func TestIsAutoReloadingAcrossRestarts(t *testing.T) {
+	c, _ := newTestCache(t, map[string]int{"a": 1})
+	if err := c.SetInterval(time.Hour); err != nil {
+		t.Fatal(err)
+	}
+	if c.IsAutoReloading() {
+		t.Fatal("IsAutoReloading = true before any start")
+	}
+	for i := range 3 {
+		c.StartAutoReload()
+		if !c.IsAutoReloading() || !c.Health().AutoReloading {
+			t.Fatalf("round %d: not auto-reloading after StartAutoReload", i)
+		}
+		c.StartAutoReload() // a second start is a no-op
+		if !c.IsAutoReloading() {
+			t.Fatalf("round %d: a repeated start stopped auto-reload", i)
+		}
+		if i%2 == 0 {
+			c.StopAutoReload()
+		} else if err := c.StopAndWait(context.Background()); err != nil {
+			t.Fatal(err)
+		}
+		if c.IsAutoReloading() || c.Health().AutoReloading {
+			t.Fatalf("round %d: still auto-reloading after stopping", i)
+		}
+	}
+	// StopAndWait on a stopped cache is harmless and leaves it stopped.
+	if err := c.StopAndWait(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if c.IsAutoReloading() {
+		t.Fatal("auto-reloading after a second StopAndWait")
+	}
+}
@@ -0,0 +1,51 @@
+package cache
+
+import "context"
+
+// SetLoader replaces the loader used by subsequent reloads, automatic or
+// manual, for example to move a live cache to a new data source without
+// recreating it. A reload already running the old loader completes
+// normally. The contents are left as they are until the next reload; to
+// validate the new source straight away, call Load and check its error.
+func (c *Cache[T]) SetLoader(fn func() (map[string]T, error)) {
+	c.SetLoaderContext(func(context.Context) (map[string]T, error) { return fn() })
+}
+
+// SetLoaderContext is SetLoader for loaders that honour cancellation, as
+// passed to NewCacheWithContext. It also replaces a StreamLoader or a
+// WithPartitionedLoader loader, and lifts a suspension caused by a Fatal
+// load error.
+func (c *Cache[T]) SetLoaderContext(fn func(ctx context.Context) (map[string]T, error)) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.loader = fn
+	c.stream = nil
+	c.partitions = nil
+	c.resumeLocked()
+}
+
+// loaders returns the current loader and stream loader, at most one of
+// which is set.
+func (c *Cache[T]) loaders() (func(context.Context) (map[string]T, error), StreamLoader[T]) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.loader, c.stream
+}
+
+// WithNilResultError makes a loader returning a nil map with a nil error
+// fail the load with ErrNilResult, keeping the previous data, for loaders
+// where that can only be a bug. By default such a result is an empty data
+// set, installed as an empty map.
+func WithNilResultError[T any](enabled bool) Option[T] {
+	return func(c *Cache[T]) {
+		c.nilResultErr = enabled
+	}
+}
+
+// checkResult applies WithNilResultError to a map loader's result.
+func (c *Cache[T]) checkResult(result map[string]T, err error) (map[string]T, error) {
+	if err == nil && result == nil && c.nilResultErr {
+		return nil, ErrNilResult
+	}
+	return result, err
+}
@@ -0,0 +1,65 @@
+package cache_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/TheOrchestraX/cache"
+)
+
+// nilAfterFirst returns a loader producing {"a": 1} once and nil after.
+func nilAfterFirst() func() (map[string]int, error) {
+	loaded := false
+	return func() (map[string]int, error) {
+		if loaded {
+			return nil, nil
+		}
+		loaded = true
+		return map[string]int{"a": 1}, nil
+	}
+}
+
+func TestNilResultIsEmpty(t *testing.T) {
+	c := cache.NewCache(nilAfterFirst(), 0, cache.WithLogLevel[int](cache.LogSilent))
+	for range 2 {
+		if err := c.Load(); err != nil {
+			t.Fatalf("Load: %v", err)
+		}
+	}
+	if n := c.Len(); n != 0 {
+		t.Fatalf("Len = %d after a nil result, want 0", n)
+	}
+	// Writes to the installed map must not panic.
+	c.Add("b", 2)
+	c.Delete("b")
+	c.Add("c", 3)
+	if v := mustGet(t, c, "c"); v != 3 {
+		t.Fatalf("Get(c) = %d, want 3", v)
+	}
+	c.SetAll(nil)
+	c.Add("d", 4)
+	mustGet(t, c, "d")
+}
+
+func TestNilResultError(t *testing.T) {
+	c := cache.NewCache(nilAfterFirst(), 0,
+		cache.WithLogLevel[int](cache.LogSilent),
+		cache.WithNilResultError[int](true))
+	if !c.Config().NilResultError {
+		t.Fatal("Config().NilResultError = false")
+	}
+	if err := c.Load(); err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if err := c.Load(); !errors.Is(err, cache.ErrNilResult) {
+		t.Fatalf("Load error = %v, want ErrNilResult", err)
+	}
+	if v := mustGet(t, c, "a"); v != 1 {
+		t.Fatalf("Get(a) = %d, want the previous data kept", v)
+	}
+	if s := c.Stats(); s.LoadErrors != 1 {
+		t.Fatalf("LoadErrors = %d, want 1", s.LoadErrors)
+	}
+	c.Add("b", 2)
+	mustGet(t, c, "b")
+}
@@ -0,0 +1,51 @@
+package cache
+
+import (
+	"fmt"
+	"log"
+)
+
+// LogLevel controls how much the cache writes to the standard logger.
+type LogLevel int
+
+const (
+	// LogSilent suppresses all output, including load errors.
+	LogSilent LogLevel = iota
+	// LogErrorsOnly logs load failures but not successful reloads.
+	LogErrorsOnly
+	// LogInfo additionally logs every successful reload. It is the default.
+	LogInfo
+	// LogDebug additionally logs diagnostic detail.
+	LogDebug
+)
+
+// String returns the level's name.
+func (l LogLevel) String() string {
+	switch l {
+	case LogSilent:
+		return "silent"
+	case LogErrorsOnly:
+		return "errors-only"
+	case LogInfo:
+		return "info"
+	case LogDebug:
+		return "debug"
+	}
+	return fmt.Sprintf("LogLevel(%d)", int(l))
+}
+
+// logf writes a log line if the cache's level is at least level.
+func (c *Cache[T]) logf(level LogLevel, format string, args ...any) {
+	if c.logLevel >= level {
+		log.Printf(format, args...)
+	}
+}
+
+// label identifies the cache in log lines: "Cache" when unnamed, keeping
+// the historical format, or `Cache "name"` otherwise.
+func (c *Cache[T]) label() string {
+	if c.name == "" {
+		return "Cache"
+	}
+	return fmt.Sprintf("Cache %q", c.name)
+}
@@ -0,0 +1,178 @@
+package cache
+
+import (
+	"container/list"
+	"sync"
+)
+
+// lru tracks the recency of use of every stored key, and the items' total
+// weight. Its list is updated by readers holding only the cache's read
+// lock, so it has a mutex of its own, always acquired after Cache.mu.
+type lru[T any] struct {
+	max       int   // item limit, 0 if none
+	maxWeight int64 // weight limit, 0 if none
+	weigher   func(key string, value T) int64
+	weight    int64 // total weight; guarded by Cache.mu
+	mu        sync.Mutex
+	order     *list.List // of keys, most recently used at the front
+}
+
+// WithMaxEntries bounds the cache to max items. When an insert or a load
+// pushes the count past max, the least recently used items are evicted and
+// reported to OnEvict hooks. Get and GetAndTouch count as uses; scans such
+// as Find and GetAll do not. A max <= 0 leaves the cache unbounded.
+func WithMaxEntries[T any](max int) Option[T] {
+	return func(c *Cache[T]) {
+		if max < 0 {
+			max = 0
+		}
+		c.limits().max = max
+		c.dropUnusedLRU()
+	}
+}
+
+// WithWeigher sets the function giving each item's weight, such as its
+// approximate size in bytes, for WithMaxWeight and Stats.Weight. It is
+// called once whenever an item is stored, under the cache lock.
+func WithWeigher[T any](fn func(key string, value T) int64) Option[T] {
+	return func(c *Cache[T]) {
+		c.limits().weigher = fn
+		c.dropUnusedLRU()
+	}
+}
+
+// WithMaxWeight bounds the total weight of the items, as given by
+// WithWeigher (each item weighs 1 without one). Least recently used items
+// are evicted until the total is back within w, as for WithMaxEntries. A
+// w <= 0 removes the bound.
+func WithMaxWeight[T any](w int64) Option[T] {
+	return func(c *Cache[T]) {
+		c.limits().maxWeight = max(w, 0)
+		c.dropUnusedLRU()
+	}
+}
+
+// limits returns the cache's lru, creating it if needed.
+func (c *Cache[T]) limits() *lru[T] {
+	if c.lru == nil {
+		c.lru = &lru[T]{order: list.New()}
+	}
+	return c.lru
+}
+
+// dropUnusedLRU discards the lru once no limit or weigher needs it.
+func (c *Cache[T]) dropUnusedLRU() {
+	if l := c.lru; l != nil && l.max == 0 && l.maxWeight == 0 && l.weigher == nil {
+		c.lru = nil
+	}
+}
+
+// weigh returns the weight of value stored under key.
+func (l *lru[T]) weigh(key string, value T) int64 {
+	if l.weigher == nil {
+		return 1
+	}
+	return l.weigher(key, value)
+}
+
+// over reports whether the cache, holding n items, exceeds a limit.
+func (l *lru[T]) over(n int) bool {
+	return l.max > 0 && n > l.max || l.maxWeight > 0 && l.weight > l.maxWeight
+}
+
+// OnEvict registers fn to be called with each item evicted to respect
+// WithMaxEntries or WithMaxWeight. Evictions do not trigger OnDelete. fn
+// runs outside the cache lock.
+func (c *Cache[T]) OnEvict(fn func(key string, value T)) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.hooks.onEvict = append(c.hooks.onEvict, fn)
+}
+
+// set records key, now stored as e, as the most recently used. old is the
+// entry e replaced, or nil.
+func (l *lru[T]) set(key string, old, e *entry[T]) {
+	e.weight = l.weigh(key, e.val())
+	l.weight += e.weight
+	if old != nil {
+		l.weight -= old.weight
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if old != nil && old.elem != nil {
+		e.elem = old.elem
+		l.order.MoveToFront(e.elem)
+		return
+	}
+	e.elem = l.order.PushFront(key)
+}
+
+// used marks e as the most recently used.
+func (l *lru[T]) used(e *entry[T]) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if e.elem != nil {
+		l.order.MoveToFront(e.elem)
+	}
+}
+
+// remove forgets the removed entry e.
+func (l *lru[T]) remove(e *entry[T]) {
+	l.weight -= e.weight
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if e.elem != nil {
+		l.order.Remove(e.elem)
+		e.elem = nil
+	}
+}
+
+// rebuild resets the list to the keys of data, in no particular order,
+// and totals their weight.
+func (l *lru[T]) rebuild(data map[string]*entry[T]) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.order.Init()
+	l.weight = 0
+	for k, e := range data {
+		e.elem = l.order.PushBack(k)
+		e.weight = l.weigh(k, e.val())
+		l.weight += e.weight
+	}
+}
+
+// oldest returns the least recently used key for which skip is false.
+func (l *lru[T]) oldest(skip func(key string) bool) (string, bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	for el := l.order.Back(); el != nil; el = el.Prev() {
+		if key := el.Value.(string); !skip(key) {
+			return key, true
+		}
+	}
+	return "", false
+}
+
+// evictLocked evicts least recently used items until the cache is within
+// its WithMaxEntries and WithMaxWeight limits, returning the evictions for
+// notify. Pinned items are never evicted, so they may keep the cache above
+// the limit. The caller must hold c.mu for writing.
+func (c *Cache[T]) evictLocked() []mutation[T] {
+	if c.lru == nil {
+		return nil
+	}
+	var evicted []mutation[T]
+	for c.lru.over(len(c.data)) {
+		key, ok := c.lru.oldest(c.isPinnedLocked)
+		if !ok {
+			break
+		}
+		e, ok := c.deleteLocked(key)
+		if !ok {
+			break
+		}
+		c.stats.evictions.Add(1)
+		evicted = append(evicted, mutation[T]{key: key, value: c.output(e.val()), deleted: true, evicted: true})
+	}
+	return evicted
+}
@@ -0,0 +1,44 @@
+package cache
+
+import (
+	"path"
+	"regexp"
+)
+
+// KeysMatching returns the keys of all live items matching a path.Match
+// style glob such as "user:*:session". A malformed pattern yields
+// path.ErrBadPattern rather than an empty result.
+func (c *Cache[T]) KeysMatching(pattern string) ([]string, error) {
+	if _, err := path.Match(pattern, ""); err != nil {
+		return nil, err
+	}
+	now := c.clock.Now()
+	c.clearExpiredData(now)
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	var keys []string
+	for k, e := range c.data {
+		if e.expired(now) {
+			continue
+		}
+		if ok, _ := path.Match(pattern, k); ok {
+			keys = append(keys, k)
+		}
+	}
+	return keys, nil
+}
+
+// KeysMatchingRegexp returns the keys of all live items matched by re.
+func (c *Cache[T]) KeysMatchingRegexp(re *regexp.Regexp) []string {
+	now := c.clock.Now()
+	c.clearExpiredData(now)
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	var keys []string
+	for k, e := range c.data {
+		if !e.expired(now) && re.MatchString(k) {
+			keys = append(keys, k)
+		}
+	}
+	return keys
+}
@@ -0,0 +1,74 @@
+package cache
+
+import (
+	"fmt"
+	"time"
+)
+
+// ExpirePolicy selects what WithMaxDataAge does once the data is too old.
+type ExpirePolicy int
+
+const (
+	// ClearData drops the contents, so reads miss until a load succeeds.
+	// Pinned items are kept.
+	ClearData ExpirePolicy = iota
+	// ServeButFlag keeps serving the contents but reports them as Expired
+	// through GetWithFreshness and Health.
+	ServeButFlag
+)
+
+// String returns the policy's name.
+func (p ExpirePolicy) String() string {
+	switch p {
+	case ClearData:
+		return "clear-data"
+	case ServeButFlag:
+		return "serve-but-flag"
+	}
+	return fmt.Sprintf("ExpirePolicy(%d)", int(p))
+}
+
+// WithMaxDataAge bounds how old the cache's data may get, for data that
+// must not be served past a deadline even when the loader is failing. Once
+// d has passed since the last successful load, the data is Expired and
+// policy applies. Under ClearData the contents are dropped after the next
+// failed load, on the next read of any kind or on the next janitor sweep,
+// whichever comes first, so no reader sees them past the deadline; later
+// writes are kept until a load succeeds.
+func WithMaxDataAge[T any](d time.Duration, policy ExpirePolicy) Option[T] {
+	return func(c *Cache[T]) {
+		c.maxDataAge = d
+		c.expirePolicy = policy
+	}
+}
+
+// dataExpiredLocked reports whether the data has outlived WithMaxDataAge
+// at now. The caller must hold c.mu.
+func (c *Cache[T]) dataExpiredLocked(now time.Time) bool {
+	return c.maxDataAge > 0 && !c.lastLoaded.IsZero() && now.Sub(c.lastLoaded) > c.maxDataAge
+}
+
+// clearExpiredData applies the ClearData policy: once the data has
+// expired, the contents are dropped, once per expiry.
+func (c *Cache[T]) clearExpiredData(now time.Time) {
+	if c.maxDataAge <= 0 || c.expirePolicy != ClearData {
+		return
+	}
+	c.mu.RLock()
+	due := !c.dataCleared && c.dataExpiredLocked(now)
+	c.mu.RUnlock()
+	if !due {
+		return
+	}
+	c.mu.Lock()
+	if c.dataCleared || !c.dataExpiredLocked(now) {
+		c.mu.Unlock()
+		return
+	}
+	c.replaceLocked(make(map[string]*entry[T]))
+	c.dataCleared = true
+	age := now.Sub(c.lastLoaded)
+	c.mu.Unlock()
+	c.logf(LogErrorsOnly, "%s data expired (last loaded %s ago); cleared", c.label(), age.Round(time.Second))
+	c.sizeChanged()
+}
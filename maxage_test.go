@@ -0,0 +1,132 @@
+package cache_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/TheOrchestraX/cache"
+	"github.com/TheOrchestraX/cache/cachetest"
+)
+
+func TestMaxDataAgeClearsForEveryReader(t *testing.T) {
+	// Each reader returns the number of items it sees.
+	readers := []struct {
+		name string
+		read func(t *testing.T, c *cache.Cache[int]) int
+	}{
+		{"Get", func(t *testing.T, c *cache.Cache[int]) int {
+			if _, ok := c.Get("a"); ok {
+				return 1
+			}
+			return 0
+		}},
+		{"Has", func(t *testing.T, c *cache.Cache[int]) int {
+			if c.Has("a") {
+				return 1
+			}
+			return 0
+		}},
+		{"GetWithMeta", func(t *testing.T, c *cache.Cache[int]) int {
+			if _, ok := c.GetWithMeta("a"); ok {
+				return 1
+			}
+			return 0
+		}},
+		{"GetAll", func(t *testing.T, c *cache.Cache[int]) int { return len(c.GetAll()) }},
+		{"GetAllInto", func(t *testing.T, c *cache.Cache[int]) int { return len(c.GetAllInto(map[string]int{})) }},
+		{"Find", func(t *testing.T, c *cache.Cache[int]) int { return len(c.Find(func(int) bool { return true })) }},
+		{"FindKeys", func(t *testing.T, c *cache.Cache[int]) int { return len(c.FindKeys(func(int) bool { return true })) }},
+		{"FindOne", func(t *testing.T, c *cache.Cache[int]) int {
+			if _, ok := c.FindOne(func(int) bool { return true }); ok {
+				return 1
+			}
+			return 0
+		}},
+		{"FindSeq", func(t *testing.T, c *cache.Cache[int]) int {
+			n := 0
+			c.FindSeq(func(int) bool { return true })(func(string, int) bool { n++; return true })
+			return n
+		}},
+		{"Range", func(t *testing.T, c *cache.Cache[int]) int {
+			n := 0
+			c.Range(func(string, int) bool { n++; return true })
+			return n
+		}},
+		{"Keys", func(t *testing.T, c *cache.Cache[int]) int { return len(c.Keys()) }},
+		{"Len", func(t *testing.T, c *cache.Cache[int]) int { return c.Len() }},
+		{"Page", func(t *testing.T, c *cache.Cache[int]) int {
+			_, total := c.Page(0, 10, nil)
+			return total
+		}},
+		{"SumBy", func(t *testing.T, c *cache.Cache[int]) int { return cache.SumBy(c, func(int) int { return 1 }) }},
+		{"Snapshot", func(t *testing.T, c *cache.Cache[int]) int { return c.Snapshot().Len() }},
+		{"SnapshotTogether", func(t *testing.T, c *cache.Cache[int]) int { return cache.SnapshotTogether(c)[0].Len() }},
+		{"EntriesWithMeta", func(t *testing.T, c *cache.Cache[int]) int { return len(c.EntriesWithMeta()) }},
+		{"Export", func(t *testing.T, c *cache.Cache[int]) int {
+			var buf bytes.Buffer
+			if err := c.Export(&buf); err != nil {
+				t.Fatal(err)
+			}
+			return bytes.Count(buf.Bytes(), []byte("\n"))
+		}},
+		{"MarshalJSON", func(t *testing.T, c *cache.Cache[int]) int {
+			b, err := json.Marshal(c)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var items map[string]int
+			json.Unmarshal(b, &items)
+			return len(items)
+		}},
+	}
+	for _, r := range readers {
+		t.Run(r.name, func(t *testing.T) {
+			c, clk := newTestCache(t, map[string]int{"a": 1, "b": 2},
+				cache.WithMaxDataAge[int](10*time.Minute, cache.ClearData))
+			clk.Advance(10 * time.Minute)
+			if n := r.read(t, c); n == 0 {
+				t.Fatal("saw no items before the data expired")
+			}
+			clk.Advance(time.Second)
+			if n := r.read(t, c); n != 0 {
+				t.Fatalf("saw %d items after the data expired, want 0", n)
+			}
+			if f := c.Freshness(); f != cache.Expired {
+				t.Fatalf("Freshness = %s, want expired", f)
+			}
+		})
+	}
+}
+
+func TestMaxDataAgeClearedByJanitor(t *testing.T) {
+	clk := cachetest.NewFakeClock(epoch)
+	c := cache.NewCache(func() (map[string]int, error) { return map[string]int{"a": 1}, nil }, 0,
+		cache.WithClock[int](clk),
+		cache.WithLogLevel[int](cache.LogSilent),
+		cache.WithJanitorInterval[int](time.Minute),
+		cache.WithMaxDataAge[int](90*time.Second, cache.ClearData))
+	if err := c.Load(); err != nil {
+		t.Fatal(err)
+	}
+	c.StartJanitor()
+	defer c.StopAutoReload()
+	waitFor(t, "the janitor ticker", func() bool { return clk.Waiters() > 0 })
+	clk.Advance(2 * time.Minute)
+	// Health does not apply the policy itself, so only the janitor can
+	// have emptied the cache.
+	waitFor(t, "the janitor to clear the data", func() bool { return c.Health().Items == 0 })
+}
+
+func TestMaxDataAgeServeButFlagKeepsData(t *testing.T) {
+	c, clk := newTestCache(t, map[string]int{"a": 1},
+		cache.WithMaxDataAge[int](time.Minute, cache.ServeButFlag))
+	clk.Advance(time.Hour)
+	if n := len(c.GetAll()); n != 1 {
+		t.Fatalf("GetAll returned %d items, want 1", n)
+	}
+	if f := c.Freshness(); f != cache.Expired {
+		t.Fatalf("Freshness = %s, want expired", f)
+	}
+}
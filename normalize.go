@@ -0,0 +1,67 @@
+package cache
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// WithKeyNormalizer canonicalizes keys with fn, for example to lower-case
+// them and trim stray whitespace, so that keys from inconsistent producers
+// do not create duplicate entries. fn is applied to the key passed to
+// every method that takes one, to the keys of every map installed by a
+// load, SetAll, Prime or an import, and to the keys a stream loader emits.
+// When several keys of one map normalize to the same key, one item is
+// kept, preferring the one whose key was already canonical, and the
+// collision is logged. Prefixes and patterns, as given to DeleteByPrefix
+// or KeysMatching, are not normalized. fn must be idempotent.
+func WithKeyNormalizer[T any](fn func(string) string) Option[T] {
+	return func(c *Cache[T]) {
+		c.keyNorm = fn
+	}
+}
+
+// normKey returns key as normalized by WithKeyNormalizer.
+func (c *Cache[T]) normKey(key string) string {
+	if c.keyNorm == nil {
+		return key
+	}
+	return c.keyNorm(key)
+}
+
+// maxMergedLogged bounds the collisions listed when normalizeItems logs.
+const maxMergedLogged = 10
+
+// normalizeItems returns items with their keys normalized, logging any
+// collisions. items itself is returned when there is no normalizer.
+func (c *Cache[T]) normalizeItems(items map[string]T) map[string]T {
+	if c.keyNorm == nil {
+		return items
+	}
+	out := make(map[string]T, len(items))
+	origin := make(map[string]string, len(items)) // normalized → original
+	var merged []string
+	for k, v := range items {
+		n := c.keyNorm(k)
+		if prev, dup := origin[n]; dup {
+			merged = append(merged, fmt.Sprintf("%q/%q→%q", min(prev, k), max(prev, k), n))
+			// Keep the canonical key's item, else that of the smallest
+			// key, so that the outcome does not depend on map order.
+			if prev == n || (k != n && prev < k) {
+				continue
+			}
+		}
+		origin[n] = k
+		out[n] = v
+	}
+	if len(merged) > 0 {
+		slices.Sort(merged)
+		more := ""
+		if len(merged) > maxMergedLogged {
+			more = fmt.Sprintf(" and %d more", len(merged)-maxMergedLogged)
+			merged = merged[:maxMergedLogged]
+		}
+		c.logf(LogErrorsOnly, "%s key normalization merged colliding keys: %s%s", c.label(), strings.Join(merged, ", "), more)
+	}
+	return out
+}
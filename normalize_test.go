@@ -0,0 +1,81 @@
+package cache_test
+
+import (
+	"bytes"
+	"log"
+	"maps"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/TheOrchestraX/cache"
+)
+
+// canonical lower-cases keys and trims surrounding whitespace.
+func canonical(k string) string { return strings.ToLower(strings.TrimSpace(k)) }
+
+func TestKeyNormalizerEntryPoints(t *testing.T) {
+	norm := cache.WithKeyNormalizer[int](canonical)
+	for _, tc := range []struct {
+		name string
+		// op writes "User:1" under some spelling; it must then read back
+		// as "user:1" under any spelling.
+		op func(c *cache.Cache[int])
+	}{
+		{"Add", func(c *cache.Cache[int]) { c.Add(" User:1", 7) }},
+		{"Set", func(c *cache.Cache[int]) { c.Set("USER:1 ", 7) }},
+		{"AddWithTTL", func(c *cache.Cache[int]) { c.AddWithTTL("User:1", 7, time.Hour) }},
+		{"GetOrCompute", func(c *cache.Cache[int]) {
+			c.GetOrCompute(" user:1", func() (int, error) { return 7, nil })
+		}},
+		{"SetAll", func(c *cache.Cache[int]) { c.SetAll(map[string]int{"User:1 ": 7}) }},
+		{"Prime", func(c *cache.Cache[int]) { c.Prime(map[string]int{"USER:1": 7}) }},
+		{"Import", func(c *cache.Cache[int]) {
+			if _, err := c.Import(strings.NewReader(`{"key":" User:1","value":7}`)); err != nil {
+				t.Fatal(err)
+			}
+		}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			c, _ := newTestCache(t, map[string]int{}, norm)
+			tc.op(c)
+			if got := c.Keys(); !slices.Equal(got, []string{"user:1"}) {
+				t.Fatalf("Keys = %q, want [user:1]", got)
+			}
+			for _, k := range []string{"user:1", "User:1 ", " USER:1"} {
+				if v := mustGet(t, c, k); v != 7 {
+					t.Fatalf("Get(%q) = %d, want 7", k, v)
+				}
+				if !c.Has(k) {
+					t.Fatalf("Has(%q) = false", k)
+				}
+			}
+			c.Delete(" User:1 ")
+			mustMiss(t, c, "user:1")
+		})
+	}
+}
+
+func TestKeyNormalizerLoadCollisions(t *testing.T) {
+	var logs bytes.Buffer
+	prev := log.Writer()
+	log.SetOutput(&logs)
+	defer log.SetOutput(prev)
+
+	data := map[string]int{"User:1": 1, "user:1": 2, " USER:1 ": 3, "User:2": 4}
+	c := cache.NewCache(func() (map[string]int, error) { return maps.Clone(data), nil }, 0,
+		cache.WithLogLevel[int](cache.LogErrorsOnly),
+		cache.WithKeyNormalizer[int](canonical))
+	if err := c.Load(); err != nil {
+		t.Fatal(err)
+	}
+	got := c.GetAll()
+	// The item whose key was already canonical wins the collision.
+	if want := map[string]int{"user:1": 2, "user:2": 4}; !maps.Equal(got, want) {
+		t.Fatalf("GetAll = %v, want %v", got, want)
+	}
+	if !strings.Contains(logs.String(), "merged colliding keys") {
+		t.Fatalf("collision not logged; log:\n%s", logs.String())
+	}
+}
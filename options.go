@@ -0,0 +1,99 @@
+package cache
+
+import "time"
+
+// Option configures optional Cache behaviour at construction time.
+type Option[T any] func(*Cache[T])
+
+// WithSlidingTTL enables sliding expiration: items inserted by Add or Load
+// expire d after insertion (unless a default TTL applies), and every
+// successful Get pushes the expiry out to d from now, so only idle items
+// expire. Scans (GetAll, Find, FindOne) do not extend expirations, and
+// items added via AddWithTTL with ttl <= 0 remain permanent.
+func WithSlidingTTL[T any](d time.Duration) Option[T] {
+	return func(c *Cache[T]) {
+		c.slidingTTL = d
+	}
+}
+
+// WithDefaultTTL makes items inserted by Add expire d after insertion.
+// AddWithTTL overrides it per item. Loaded items are only affected when
+// WithDefaultTTLOnLoad is also set.
+func WithDefaultTTL[T any](d time.Duration) Option[T] {
+	return func(c *Cache[T]) {
+		c.defaultTTL = d
+	}
+}
+
+// WithDefaultTTLOnLoad applies the default TTL to items installed by Load
+// as well as those inserted by Add.
+func WithDefaultTTLOnLoad[T any](enabled bool) Option[T] {
+	return func(c *Cache[T]) {
+		c.defaultTTLOnLoad = enabled
+	}
+}
+
+// WithClock replaces the real clock, typically with a fake from the
+// cachetest package so tests can step time manually.
+func WithClock[T any](clock Clock) Option[T] {
+	return func(c *Cache[T]) {
+		c.clock = clock
+	}
+}
+
+// WithCloner makes every read (Get, GetAll, Find, FindOne and the other
+// value-returning methods) return fn(value) instead of the stored value, so
+// callers can never mutate cached data through shared slices or maps.
+// Predicates passed to Find and friends still see the stored value.
+func WithCloner[T any](fn func(T) T) Option[T] {
+	return func(c *Cache[T]) {
+		c.cloner = fn
+	}
+}
+
+// WithCloneOnAdd additionally stores a clone of values passed to Add,
+// AddWithTTL and Txn, isolating the cache from later changes the caller
+// makes to them. It has no effect without WithCloner. Loaded data is
+// stored as returned by the loader.
+func WithCloneOnAdd[T any](enabled bool) Option[T] {
+	return func(c *Cache[T]) {
+		c.cloneOnAdd = enabled
+	}
+}
+
+// WithName names the cache. The name appears in every log line, which
+// tells caches apart when a process runs several of them.
+func WithName[T any](name string) Option[T] {
+	return func(c *Cache[T]) {
+		c.name = name
+	}
+}
+
+// WithLogLevel sets how much the cache logs. Load errors are logged at every
+// level except LogSilent, which must be chosen explicitly.
+func WithLogLevel[T any](level LogLevel) Option[T] {
+	return func(c *Cache[T]) {
+		c.logLevel = level
+	}
+}
+
+// WithQuietReloads suppresses the per-reload success line while still
+// logging errors. It is shorthand for WithLogLevel(LogErrorsOnly).
+func WithQuietReloads[T any](quiet bool) Option[T] {
+	return func(c *Cache[T]) {
+		if quiet {
+			c.logLevel = min(c.logLevel, LogErrorsOnly)
+		} else {
+			c.logLevel = max(c.logLevel, LogInfo)
+		}
+	}
+}
+
+// WithEqual supplies a value equality func. Reloads then detect changed
+// values as well as added and removed keys; without it only key sets are
+// compared.
+func WithEqual[T any](fn func(a, b T) bool) Option[T] {
+	return func(c *Cache[T]) {
+		c.equal = fn
+	}
+}
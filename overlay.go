@@ -0,0 +1,117 @@
+package cache
+
+import "maps"
+
+// ReadView is the read side of a cache, implemented by ReadOnlyCache and by
+// the views WithOverrides and Union return.
+type ReadView[T any] interface {
+	Get(key string) (T, bool)
+	Has(key string) bool
+	GetAll() map[string]T
+	Find(predicate func(T) bool) []T
+	FindOne(predicate func(T) bool) (T, bool)
+	Len() int
+	Keys() []string
+	Range(fn func(key string, value T) bool)
+}
+
+// overlay is a ReadView layering overrides on top of a Cache.
+type overlay[T any] struct {
+	c         *Cache[T]
+	overrides map[string]T
+}
+
+// WithOverrides returns a read view in which the items of overrides take
+// the place of, or are added to, the items of the cache, for per-request
+// feature overrides and experiments. The cache itself is not modified, and
+// the view reads its live data, so it observes reloads. overrides is
+// copied, so the caller may reuse it afterwards.
+func (c *Cache[T]) WithOverrides(overrides map[string]T) ReadView[T] {
+	return &overlay[T]{c: c, overrides: c.normalizeItems(maps.Clone(overrides))}
+}
+
+// Get returns the overriding item for a key if there is one, and the
+// cache's item otherwise.
+func (o *overlay[T]) Get(key string) (T, bool) {
+	if v, ok := o.overrides[o.c.normKey(key)]; ok {
+		return v, true
+	}
+	return o.c.Get(key)
+}
+
+// Has reports whether the overrides or the cache hold key.
+func (o *overlay[T]) Has(key string) bool {
+	if _, ok := o.overrides[o.c.normKey(key)]; ok {
+		return true
+	}
+	return o.c.Has(key)
+}
+
+// GetAll returns the cache's items merged with the overrides.
+func (o *overlay[T]) GetAll() map[string]T {
+	all := o.c.GetAll()
+	maps.Copy(all, o.overrides)
+	return all
+}
+
+// Find returns all items of the view satisfying predicate.
+func (o *overlay[T]) Find(predicate func(T) bool) []T {
+	var found []T
+	o.Range(func(_ string, v T) bool {
+		if predicate(v) {
+			found = append(found, v)
+		}
+		return true
+	})
+	return found
+}
+
+// FindOne returns the first item of the view satisfying predicate, or false
+// if none, checking the overrides first.
+func (o *overlay[T]) FindOne(predicate func(T) bool) (T, bool) {
+	var found T
+	var ok bool
+	o.Range(func(_ string, v T) bool {
+		if predicate(v) {
+			found, ok = v, true
+		}
+		return !ok
+	})
+	return found, ok
+}
+
+// Len returns the number of items in the view.
+func (o *overlay[T]) Len() int {
+	n := 0
+	o.Range(func(string, T) bool {
+		n++
+		return true
+	})
+	return n
+}
+
+// Keys returns the keys of all items in the view, in no particular order.
+func (o *overlay[T]) Keys() []string {
+	keys := make([]string, 0, len(o.overrides))
+	o.Range(func(k string, _ T) bool {
+		keys = append(keys, k)
+		return true
+	})
+	return keys
+}
+
+// Range calls fn for each item of the view until fn returns false, the
+// overrides first. See Cache.Range.
+func (o *overlay[T]) Range(fn func(key string, value T) bool) {
+	for k, v := range o.overrides {
+		if !fn(k, v) {
+			return
+		}
+	}
+	o.c.Range(func(k string, v T) bool {
+		if _, ok := o.overrides[k]; ok {
+			return true
+		}
+		return fn(k, v)
+	})
+}
@@ -0,0 +1,64 @@
+package cache_test
+
+import (
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/TheOrchestraX/cache"
+)
+
+func TestOverlayReadsDuringReloads(t *testing.T) {
+	var version atomic.Int32
+	c := cache.NewCache(func() (map[string]int, error) {
+		v := int(version.Add(1))
+		return map[string]int{"flag": v, "base": v}, nil
+	}, 0, cache.WithLogLevel[int](cache.LogSilent))
+	if err := c.Load(); err != nil {
+		t.Fatal(err)
+	}
+	view := c.WithOverrides(map[string]int{"flag": -1, "extra": -2})
+
+	const rounds = 500
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for range rounds {
+			c.Load()
+		}
+	}()
+	for range 4 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for version.Load() <= rounds {
+				if v, ok := view.Get("flag"); !ok || v != -1 {
+					t.Errorf("Get(flag) = %d, %v; want the override -1", v, ok)
+					return
+				}
+				if v, ok := view.Get("base"); !ok || v < 1 {
+					t.Errorf("Get(base) = %d, %v; want a loaded version", v, ok)
+					return
+				}
+				all := view.GetAll()
+				if len(all) != 3 || all["flag"] != -1 || all["extra"] != -2 || all["base"] < 1 {
+					t.Errorf("GetAll = %v mid-reload", all)
+					return
+				}
+				if n := view.Len(); n != 3 {
+					t.Errorf("Len = %d mid-reload, want 3", n)
+					return
+				}
+				if !view.Has("extra") {
+					t.Error("Has(extra) = false mid-reload")
+					return
+				}
+			}
+		}()
+	}
+	wg.Wait()
+	if v, _ := view.Get("base"); v != rounds+1 {
+		t.Fatalf("Get(base) = %d after the reloads, want %d", v, rounds+1)
+	}
+}
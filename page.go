@@ -0,0 +1,70 @@
+package cache
+
+import (
+	"math"
+	"slices"
+)
+
+// Page returns up to limit entries starting at offset, ordered by key using
+// less (natural string order when nil), plus the total number of live items.
+// Offsets past the end yield an empty slice. When less is nil the cached
+// order behind SortedKeys is reused, so paging an unchanged cache does not
+// re-sort it.
+func (c *Cache[T]) Page(offset, limit int, less func(a, b string) bool) ([]Entry[T], int) {
+	now := c.clock.Now()
+	c.clearExpiredData(now)
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	var keys []string
+	if less == nil {
+		keys = c.sortedKeysLocked(now)
+	} else {
+		keys = make([]string, 0, len(c.data))
+		for k, e := range c.data {
+			if !e.expired(now) {
+				keys = append(keys, k)
+			}
+		}
+		slices.SortFunc(keys, func(a, b string) int {
+			switch {
+			case less(a, b):
+				return -1
+			case less(b, a):
+				return 1
+			}
+			return 0
+		})
+	}
+
+	total := len(keys)
+	if offset < 0 {
+		offset = 0
+	}
+	if offset >= total || limit <= 0 {
+		return []Entry[T]{}, total
+	}
+	// offset+limit may overflow, so clamp limit before adding.
+	if limit > total-offset {
+		limit = total - offset
+	}
+	end := offset + limit
+	page := make([]Entry[T], 0, end-offset)
+	for _, k := range keys[offset:end] {
+		page = append(page, Entry[T]{Key: k, Value: c.output(c.data[k].val())})
+	}
+	return page, total
+}
+
+// Entries returns every live item paired with its key, taken from one
+// consistent view of the contents and sorted by key: in natural string
+// order, or by less if one is given. The result suits rendering with
+// text/template or html/template.
+func (c *Cache[T]) Entries(less ...func(a, b string) bool) []Entry[T] {
+	var by func(a, b string) bool
+	if len(less) > 0 {
+		by = less[0]
+	}
+	entries, _ := c.Page(0, math.MaxInt, by)
+	return entries
+}
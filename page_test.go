@@ -0,0 +1,59 @@
+package cache_test
+
+import (
+	"math"
+	"testing"
+)
+
+func pageKeys(t *testing.T, offset, limit int) ([]string, int) {
+	t.Helper()
+	c, _ := newTestCache(t, map[string]int{"a": 1, "b": 2, "c": 3, "d": 4})
+	page, total := c.Page(offset, limit, nil)
+	keys := make([]string, len(page))
+	for i, e := range page {
+		keys[i] = e.Key
+	}
+	return keys, total
+}
+
+func TestPage(t *testing.T) {
+	tests := []struct {
+		offset, limit int
+		want          []string
+	}{
+		{0, 2, []string{"a", "b"}},
+		{2, 2, []string{"c", "d"}},
+		{3, 10, []string{"d"}},
+		{4, 1, []string{}},
+		{100, 1, []string{}},
+		{-5, 1, []string{"a"}},
+		{0, 0, []string{}},
+		{0, -1, []string{}},
+		{1, math.MaxInt, []string{"b", "c", "d"}},
+		{math.MaxInt, math.MaxInt, []string{}},
+	}
+	for _, tt := range tests {
+		got, total := pageKeys(t, tt.offset, tt.limit)
+		if total != 4 {
+			t.Errorf("Page(%d, %d) total = %d, want 4", tt.offset, tt.limit, total)
+		}
+		if len(got) != len(tt.want) {
+			t.Errorf("Page(%d, %d) = %v, want %v", tt.offset, tt.limit, got, tt.want)
+			continue
+		}
+		for i := range got {
+			if got[i] != tt.want[i] {
+				t.Errorf("Page(%d, %d) = %v, want %v", tt.offset, tt.limit, got, tt.want)
+				break
+			}
+		}
+	}
+}
+
+func TestPageCustomOrder(t *testing.T) {
+	c, _ := newTestCache(t, map[string]int{"a": 1, "b": 2, "c": 3})
+	page, _ := c.Page(0, 2, func(a, b string) bool { return a > b })
+	if len(page) != 2 || page[0].Key != "c" || page[1].Key != "b" {
+		t.Fatalf("Page with reverse order = %+v", page)
+	}
+}
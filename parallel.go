@@ -0,0 +1,138 @@
+package cache
+
+import (
+	"context"
+	"fmt"
+	"runtime"
+	"sync"
+)
+
+// FindParallel returns all items satisfying predicate, evaluating it across
+// workers goroutines (GOMAXPROCS when workers <= 0). The read lock is held
+// only while collecting entry references, not while predicate runs, so
+// writers are not blocked by slow predicates. Result order is unspecified
+// unless WithStableIteration is set.
+func (c *Cache[T]) FindParallel(predicate func(T) bool, workers int) []T {
+	if workers <= 0 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+	now := c.clock.Now()
+	c.clearExpiredData(now)
+	c.mu.RLock()
+	entries := make([]*entry[T], 0, len(c.data))
+	c.eachLocked(now, func(_ string, e *entry[T]) bool {
+		entries = append(entries, e)
+		return true
+	})
+	c.mu.RUnlock()
+
+	workers = min(workers, len(entries))
+	if workers <= 1 {
+		var results []T
+		for _, e := range entries {
+			if v := e.val(); predicate(v) {
+				results = append(results, c.output(v))
+			}
+		}
+		return results
+	}
+
+	partial := make([][]T, workers)
+	chunk := (len(entries) + workers - 1) / workers
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		lo := min(w*chunk, len(entries))
+		hi := min(lo+chunk, len(entries))
+		wg.Add(1)
+		go func(w int, part []*entry[T]) {
+			defer wg.Done()
+			for _, e := range part {
+				if v := e.val(); predicate(v) {
+					partial[w] = append(partial[w], c.output(v))
+				}
+			}
+		}(w, entries[lo:hi])
+	}
+	wg.Wait()
+
+	var results []T
+	for _, p := range partial {
+		results = append(results, p...)
+	}
+	return results
+}
+
+// ForEachParallel calls fn for every live item, spread over a pool of
+// workers goroutines (GOMAXPROCS when workers <= 0), and returns once all
+// calls have finished. It iterates a consistent snapshot taken under the
+// read lock, which is released before fn first runs, so fn may modify the
+// cache. If fn panics, no further items are dispatched and the panic is
+// returned as an error wrapping ErrCallbackPanic.
+func (c *Cache[T]) ForEachParallel(workers int, fn func(key string, value T)) error {
+	return c.ForEachParallelContext(context.Background(), workers, fn)
+}
+
+// ForEachParallelContext is ForEachParallel, stopping early once ctx is
+// done. Items already handed to workers are finished; ctx's error is
+// returned.
+func (c *Cache[T]) ForEachParallelContext(ctx context.Context, workers int, fn func(key string, value T)) error {
+	if workers <= 0 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+	type item struct {
+		key string
+		e   *entry[T]
+	}
+	now := c.clock.Now()
+	c.mu.RLock()
+	items := make([]item, 0, len(c.data))
+	c.eachLocked(now, func(k string, e *entry[T]) bool {
+		items = append(items, item{k, e})
+		return true
+	})
+	c.mu.RUnlock()
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	var (
+		wg       sync.WaitGroup
+		panicErr error
+		once     sync.Once
+	)
+	work := make(chan item)
+	for w := 0; w < min(workers, len(items)); w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for it := range work {
+				func() {
+					defer func() {
+						if r := recover(); r != nil {
+							once.Do(func() {
+								panicErr = fmt.Errorf("%w: key %q: %v", ErrCallbackPanic, it.key, r)
+							})
+							cancel()
+						}
+					}()
+					fn(it.key, c.output(it.e.val()))
+				}()
+			}
+		}()
+	}
+	var err error
+dispatch:
+	for _, it := range items {
+		select {
+		case work <- it:
+		case <-ctx.Done():
+			err = ctx.Err()
+			break dispatch
+		}
+	}
+	close(work)
+	wg.Wait()
+	if panicErr != nil {
+		return panicErr
+	}
+	return err
+}
@@ -0,0 +1,55 @@
+package cache_test
+
+import (
+	"errors"
+	"slices"
+	"sync"
+	"testing"
+
+	"github.com/TheOrchestraX/cache"
+)
+
+func TestFindParallelMatchesFind(t *testing.T) {
+	items, _ := benchItems(10_000)
+	c, _ := newTestCache(t, items)
+	even := func(v int) bool { return v%2 == 0 }
+	want := c.Find(even)
+	slices.Sort(want)
+	for _, workers := range []int{0, 1, 3, 64, 100_000} {
+		got := c.FindParallel(even, workers)
+		slices.Sort(got)
+		if !slices.Equal(got, want) {
+			t.Fatalf("FindParallel(workers=%d) found %d items, want %d", workers, len(got), len(want))
+		}
+	}
+	empty, _ := newTestCache(t, map[string]int{})
+	if got := empty.FindParallel(even, 4); len(got) != 0 {
+		t.Fatalf("FindParallel on an empty cache = %v", got)
+	}
+}
+
+func TestForEachParallelVisitsAllAndRecoversPanics(t *testing.T) {
+	items, _ := benchItems(1000)
+	c, _ := newTestCache(t, items)
+	var mu sync.Mutex
+	seen := make(map[string]int)
+	if err := c.ForEachParallel(8, func(k string, v int) {
+		mu.Lock()
+		seen[k] = v
+		mu.Unlock()
+		c.Add(k+"-copy", v) // fn may write to the cache
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if len(seen) != len(items) {
+		t.Fatalf("visited %d items, want %d", len(seen), len(items))
+	}
+	err := c.ForEachParallel(4, func(k string, v int) {
+		if v == 7 {
+			panic("boom")
+		}
+	})
+	if !errors.Is(err, cache.ErrCallbackPanic) {
+		t.Fatalf("ForEachParallel with a panicking fn = %v, want ErrCallbackPanic", err)
+	}
+}
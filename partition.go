@@ -0,0 +1,162 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+)
+
+// PartialFailurePolicy selects what a load by WithPartitionedLoader does
+// when some of its partitions fail.
+type PartialFailurePolicy int
+
+const (
+	// FailWholeLoad fails the load if any partition fails, keeping the
+	// previous data.
+	FailWholeLoad PartialFailurePolicy = iota
+	// InstallPartial installs the data of the partitions that succeeded,
+	// failing the load only if all of them fail. The failures are logged
+	// and recorded in PartitionStats and Stats.PartialLoads.
+	InstallPartial
+)
+
+// String returns the policy's name.
+func (p PartialFailurePolicy) String() string {
+	switch p {
+	case FailWholeLoad:
+		return "fail-whole-load"
+	case InstallPartial:
+		return "install-partial"
+	}
+	return fmt.Sprintf("PartialFailurePolicy(%d)", int(p))
+}
+
+// PartitionStats describes one partition of a WithPartitionedLoader loader.
+type PartitionStats struct {
+	// Loads and Failures count the partition's successful and failed runs.
+	Loads    uint64
+	Failures uint64
+	// LastErr is the error of its latest run, nil if that succeeded.
+	LastErr error
+	// LastDuration is how long its latest run took, and Items how many
+	// items it returned, 0 if it failed.
+	LastDuration time.Duration
+	Items        int
+}
+
+// partitions is the state behind WithPartitionedLoader.
+type partitions[T any] struct {
+	parts  []func(ctx context.Context) (map[string]T, error)
+	policy PartialFailurePolicy
+
+	mu    sync.Mutex
+	stats []PartitionStats
+}
+
+// WithPartitionedLoader makes the cache's data the union of the maps
+// returned by parts, which run concurrently on each load. It replaces the
+// loader passed to NewCache, which may be nil. Keys returned by several
+// partitions are logged, the lowest-numbered partition's item winning.
+// policy decides whether a failed partition fails the whole load; each
+// partition's outcome and duration are reported by PartitionStats.
+func WithPartitionedLoader[T any](parts []func(ctx context.Context) (map[string]T, error), policy PartialFailurePolicy) Option[T] {
+	return func(c *Cache[T]) {
+		p := &partitions[T]{
+			parts:  slices.Clone(parts),
+			policy: policy,
+			stats:  make([]PartitionStats, len(parts)),
+		}
+		c.partitions = p
+		c.loader = func(ctx context.Context) (map[string]T, error) {
+			return c.loadPartitions(ctx, p)
+		}
+	}
+}
+
+// loadPartitions runs every partition of p and merges their results.
+func (c *Cache[T]) loadPartitions(ctx context.Context, p *partitions[T]) (map[string]T, error) {
+	results := make([]map[string]T, len(p.parts))
+	errs := make([]error, len(p.parts))
+	took := make([]time.Duration, len(p.parts))
+	var wg sync.WaitGroup
+	for i, part := range p.parts {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			start := c.clock.Now()
+			defer func() {
+				if r := recover(); r != nil {
+					errs[i] = fmt.Errorf("%w: %v", ErrLoaderPanic, r)
+				}
+				took[i] = c.clock.Now().Sub(start)
+			}()
+			results[i], errs[i] = c.checkResult(part(ctx))
+		}()
+	}
+	wg.Wait()
+
+	p.mu.Lock()
+	var failed []error
+	for i, err := range errs {
+		s := &p.stats[i]
+		s.LastErr, s.LastDuration, s.Items = err, took[i], len(results[i])
+		if err != nil {
+			s.Failures++
+			failed = append(failed, fmt.Errorf("partition %d: %w", i, err))
+		} else {
+			s.Loads++
+		}
+	}
+	p.mu.Unlock()
+
+	if len(failed) > 0 && (p.policy == FailWholeLoad || len(failed) == len(p.parts)) {
+		return nil, errors.Join(failed...)
+	}
+	if len(failed) > 0 {
+		c.stats.partialLoads.Add(1)
+		c.logf(LogErrorsOnly, "%s installing partial data: %v", c.label(), errors.Join(failed...))
+	}
+
+	merged := make(map[string]T)
+	var collisions []string
+	for i := len(results) - 1; i >= 0; i-- {
+		for k, v := range results[i] {
+			if _, dup := merged[k]; dup {
+				collisions = append(collisions, k)
+			}
+			merged[k] = v
+		}
+	}
+	if len(collisions) > 0 {
+		slices.Sort(collisions)
+		collisions = slices.Compact(collisions)
+		c.logf(LogErrorsOnly, "%s partitions returned %d keys more than once: %q", c.label(), len(collisions), collisions)
+	}
+	return merged, nil
+}
+
+// resetCounts zeroes the partitions' counters, for ResetStats.
+func (p *partitions[T]) resetCounts() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	for i := range p.stats {
+		p.stats[i].Loads, p.stats[i].Failures = 0, 0
+	}
+}
+
+// PartitionStats returns the statistics of each WithPartitionedLoader
+// partition, in the order the partitions were given, or nil without one.
+func (c *Cache[T]) PartitionStats() []PartitionStats {
+	c.mu.RLock()
+	p := c.partitions
+	c.mu.RUnlock()
+	if p == nil {
+		return nil
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return slices.Clone(p.stats)
+}
@@ -0,0 +1,136 @@
+package cache
+
+import (
+	"context"
+	"slices"
+	"time"
+)
+
+// Pending is a reload prepared by PrepareReload, holding the loaded
+// data until it is committed or discarded.
+type Pending[T any] struct {
+	c        *Cache[T]
+	data     map[string]*entry[T]
+	loadSeq  uint64 // c.loadSeq when prepared
+	diff     ReloadDiff
+	prepared time.Time
+}
+
+// ReloadDiff lists how a prepared reload would change the cache's keys,
+// each list sorted. Changed is only filled in with WithEqual set.
+type ReloadDiff struct {
+	Added   []string
+	Removed []string
+	Changed []string
+}
+
+// PrepareReload runs the loader but, instead of swapping in the result,
+// returns it as a Pending, so that a person or a checker can inspect
+// it before calling Commit, or Discard. Only one prepared reload may exist
+// at a time; PrepareReload returns ErrReloadPending while another is open.
+// A loader error is returned without counting as a failed load, since the
+// cache itself is not affected.
+func (c *Cache[T]) PrepareReload(ctx context.Context) (*Pending[T], error) {
+	c.mu.Lock()
+	if c.pending != nil {
+		c.mu.Unlock()
+		return nil, ErrReloadPending
+	}
+	p := &Pending[T]{c: c, loadSeq: c.loadSeq}
+	c.pending = p
+	c.mu.Unlock()
+
+	data, err := c.fetch(ctx)
+	if err == nil {
+		err = ctx.Err()
+	}
+	if err != nil {
+		p.Discard()
+		return nil, err
+	}
+	now := c.clock.Now()
+	p.data, p.prepared = data, now
+	c.mu.RLock()
+	for k, e := range data {
+		prev, ok := c.data[k]
+		switch {
+		case !ok || prev.expired(now):
+			p.diff.Added = append(p.diff.Added, k)
+		case c.equal != nil && !c.equal(prev.val(), e.val()):
+			p.diff.Changed = append(p.diff.Changed, k)
+		}
+	}
+	for k, e := range c.data {
+		if _, ok := data[k]; !ok && !e.expired(now) {
+			p.diff.Removed = append(p.diff.Removed, k)
+		}
+	}
+	c.mu.RUnlock()
+	slices.Sort(p.diff.Added)
+	slices.Sort(p.diff.Removed)
+	slices.Sort(p.diff.Changed)
+	return p, nil
+}
+
+// Len returns the number of items the reload would install.
+func (p *Pending[T]) Len() int {
+	return len(p.data)
+}
+
+// Get returns the item the reload would install for key.
+func (p *Pending[T]) Get(key string) (T, bool) {
+	e, ok := p.data[p.c.normKey(key)]
+	if !ok {
+		var zero T
+		return zero, false
+	}
+	return p.c.output(e.val()), true
+}
+
+// Diff returns how the reload would change the cache's keys, compared
+// with its contents when the reload was prepared.
+func (p *Pending[T]) Diff() ReloadDiff {
+	return p.diff
+}
+
+// PreparedAt returns when the reload's data was loaded.
+func (p *Pending[T]) PreparedAt() time.Time {
+	return p.prepared
+}
+
+// Commit swaps the prepared data in, as a successful load would. It fails
+// with ErrStaleReload if another load has succeeded since the reload was
+// prepared, or if it was already committed or discarded, and with
+// ErrFrozen while the cache is frozen; the prepared reload is closed
+// either way.
+func (p *Pending[T]) Commit() error {
+	c := p.c
+	stale := false
+	ok := c.install(p.data, func() bool {
+		if c.pending != p || c.loadSeq != p.loadSeq {
+			stale = true
+			return false
+		}
+		c.pending = nil
+		return true
+	})
+	switch {
+	case ok:
+		return nil
+	case stale:
+		p.Discard()
+		return ErrStaleReload
+	}
+	p.Discard()
+	return ErrFrozen
+}
+
+// Discard throws the prepared data away, allowing another PrepareReload.
+// It is safe to call more than once, and after Commit.
+func (p *Pending[T]) Discard() {
+	p.c.mu.Lock()
+	defer p.c.mu.Unlock()
+	if p.c.pending == p {
+		p.c.pending = nil
+	}
+}
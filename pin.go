@@ -0,0 +1,68 @@
+package cache
+
+import "slices"
+
+// Pin protects key from disappearing: while pinned, its item never
+// expires, is never evicted by WithMaxEntries, survives Clear, and is kept
+// with its old value when a reload no longer produces the key (a warning
+// is logged). Writes and explicit deletes still apply. Pinning an absent
+// key protects it once it is stored.
+func (c *Cache[T]) Pin(key string) {
+	key = c.normKey(key)
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.pinned == nil {
+		c.pinned = make(map[string]struct{})
+	}
+	c.pinned[key] = struct{}{}
+	if e, ok := c.data[key]; ok && !e.expired(c.clock.Now()) {
+		e.expiresAt.Store(0)
+	}
+}
+
+// Unpin removes the protection added by Pin. The item itself is kept, with
+// no expiry until it is next written.
+func (c *Cache[T]) Unpin(key string) {
+	key = c.normKey(key)
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.pinned, key)
+}
+
+// PinnedKeys returns the pinned keys in ascending order, including any not
+// currently stored.
+func (c *Cache[T]) PinnedKeys() []string {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	keys := make([]string, 0, len(c.pinned))
+	for k := range c.pinned {
+		keys = append(keys, k)
+	}
+	slices.Sort(keys)
+	return keys
+}
+
+// isPinnedLocked reports whether key is pinned. The caller must hold c.mu.
+func (c *Cache[T]) isPinnedLocked(key string) bool {
+	_, ok := c.pinned[key]
+	return ok
+}
+
+// keepPinnedLocked prepares data, about to replace the contents, for the
+// pinned keys: their new entries lose any expiry, and those data lacks
+// keep their current entry. It returns the keys kept. The caller must hold
+// c.mu for writing.
+func (c *Cache[T]) keepPinnedLocked(data map[string]*entry[T]) []string {
+	var kept []string
+	for k := range c.pinned {
+		if e, ok := data[k]; ok {
+			e.expiresAt.Store(0)
+			continue
+		}
+		if old, ok := c.data[k]; ok {
+			data[k] = old
+			kept = append(kept, k)
+		}
+	}
+	return kept
+}
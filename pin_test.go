@@ -0,0 +1,104 @@
+package cache_test
+
+import (
+	"bytes"
+	"log"
+	"maps"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/TheOrchestraX/cache"
+	"github.com/TheOrchestraX/cache/cachetest"
+)
+
+func TestPinSurvivesExpiry(t *testing.T) {
+	c, clk := newTestCache(t, map[string]int{})
+	c.AddWithTTL("kill", 1, time.Minute)
+	c.Pin("kill")
+	c.AddWithTTL("later", 2, time.Minute)
+	c.Pin("later") // pinned after being stored, then rewritten
+	c.AddWithTTL("later", 3, time.Minute)
+	clk.Advance(time.Hour)
+	if v := mustGet(t, c, "kill"); v != 1 {
+		t.Fatalf("Get(kill) = %d, want 1", v)
+	}
+	if v := mustGet(t, c, "later"); v != 3 {
+		t.Fatalf("Get(later) = %d, want 3", v)
+	}
+}
+
+func TestPinSurvivesEviction(t *testing.T) {
+	c, _ := newTestCache(t, map[string]int{}, cache.WithMaxEntries[int](2))
+	c.Add("kill", 1)
+	c.Pin("kill")
+	for i, k := range []string{"a", "b", "c", "d"} {
+		c.Add(k, i)
+	}
+	mustGet(t, c, "kill")
+	mustMiss(t, c, "a")
+}
+
+func TestPinSurvivesClear(t *testing.T) {
+	c, _ := newTestCache(t, map[string]int{"kill": 1, "other": 2})
+	c.Pin("kill")
+	c.Clear()
+	mustGet(t, c, "kill")
+	mustMiss(t, c, "other")
+}
+
+func TestPinSurvivesReloadRemoval(t *testing.T) {
+	var logs bytes.Buffer
+	prev := log.Writer()
+	log.SetOutput(&logs)
+	defer log.SetOutput(prev)
+
+	data := map[string]int{"kill": 1, "other": 2}
+	c := cache.NewCache(func() (map[string]int, error) { return maps.Clone(data), nil }, 0,
+		cache.WithClock[int](cachetest.NewFakeClock(epoch)),
+		cache.WithLogLevel[int](cache.LogErrorsOnly))
+	if err := c.Load(); err != nil {
+		t.Fatal(err)
+	}
+	c.Pin("kill")
+	data = map[string]int{"other": 3}
+	if err := c.Load(); err != nil {
+		t.Fatal(err)
+	}
+	if v := mustGet(t, c, "kill"); v != 1 {
+		t.Fatalf("Get(kill) = %d, want the old value 1", v)
+	}
+	if !strings.Contains(logs.String(), `dropped pinned keys ["kill"]`) {
+		t.Fatalf("no warning logged for the dropped pinned key; log:\n%s", logs.String())
+	}
+
+	// A reload producing the key updates it as usual.
+	data = map[string]int{"kill": 4}
+	c.Load()
+	if v := mustGet(t, c, "kill"); v != 4 {
+		t.Fatalf("Get(kill) = %d, want 4", v)
+	}
+}
+
+func TestUnpinRemovesProtection(t *testing.T) {
+	c, _ := newTestCache(t, map[string]int{"a": 1, "b": 2})
+	c.Pin("b")
+	c.Pin("a")
+	c.Pin("absent")
+	if got, want := c.PinnedKeys(), []string{"a", "absent", "b"}; !slices.Equal(got, want) {
+		t.Fatalf("PinnedKeys = %v, want %v", got, want)
+	}
+	c.Unpin("a")
+	c.Unpin("absent")
+	if got, want := c.PinnedKeys(), []string{"b"}; !slices.Equal(got, want) {
+		t.Fatalf("PinnedKeys = %v, want %v", got, want)
+	}
+	c.Clear()
+	mustMiss(t, c, "a")
+	mustGet(t, c, "b")
+
+	// Explicit deletes apply even to pinned keys.
+	c.Delete("b")
+	mustMiss(t, c, "b")
+}
@@ -0,0 +1,126 @@
+package cache
+
+import (
+	"slices"
+	"strings"
+)
+
+// sortedKeys is an ordered key set used to answer prefix queries without
+// scanning the whole map. Inserts and removals cost O(n) element moves, which
+// suits read-heavy caches that mostly change through reloads.
+type sortedKeys struct {
+	keys []string
+}
+
+// insert adds key, keeping the set sorted.
+func (s *sortedKeys) insert(key string) {
+	i, found := slices.BinarySearch(s.keys, key)
+	if !found {
+		s.keys = slices.Insert(s.keys, i, key)
+	}
+}
+
+// remove deletes key if present.
+func (s *sortedKeys) remove(key string) {
+	if i, found := slices.BinarySearch(s.keys, key); found {
+		s.keys = slices.Delete(s.keys, i, i+1)
+	}
+}
+
+// reset replaces the set with keys, taking ownership of the slice.
+func (s *sortedKeys) reset(keys []string) {
+	slices.Sort(keys)
+	s.keys = keys
+}
+
+// withPrefix returns the sorted keys starting with prefix. The result
+// aliases the index and must not be modified.
+func (s *sortedKeys) withPrefix(prefix string) []string {
+	i, _ := slices.BinarySearch(s.keys, prefix)
+	j := i
+	for j < len(s.keys) && strings.HasPrefix(s.keys[j], prefix) {
+		j++
+	}
+	return s.keys[i:j]
+}
+
+// WithPrefixIndex maintains a sorted key index on every mutation so that
+// prefix queries cost O(log n + matches) instead of a full scan. Writes
+// become O(n) in the worst case, so enable it for read-heavy caches.
+func WithPrefixIndex[T any](enabled bool) Option[T] {
+	return func(c *Cache[T]) {
+		if enabled {
+			c.prefixIndex = &sortedKeys{}
+		} else {
+			c.prefixIndex = nil
+		}
+	}
+}
+
+// KeysWithPrefix returns the keys of all live items starting with prefix.
+// Results are sorted when the prefix index is enabled.
+func (c *Cache[T]) KeysWithPrefix(prefix string) []string {
+	now := c.clock.Now()
+	c.clearExpiredData(now)
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	var keys []string
+	c.scanPrefixLocked(prefix, func(k string, e *entry[T]) {
+		if !e.expired(now) {
+			keys = append(keys, k)
+		}
+	})
+	return keys
+}
+
+// GetByPrefix returns a map of all live items whose keys start with prefix.
+func (c *Cache[T]) GetByPrefix(prefix string) map[string]T {
+	now := c.clock.Now()
+	c.clearExpiredData(now)
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	result := make(map[string]T)
+	c.scanPrefixLocked(prefix, func(k string, e *entry[T]) {
+		if !e.expired(now) {
+			result[k] = c.output(e.val())
+		}
+	})
+	return result
+}
+
+// DeleteByPrefix removes every item whose key starts with prefix and
+// returns how many were removed.
+func (c *Cache[T]) DeleteByPrefix(prefix string) int {
+	if c.rejectFrozen("delete") {
+		return 0
+	}
+	c.mu.Lock()
+	var muts []mutation[T]
+	c.scanPrefixLocked(prefix, func(k string, _ *entry[T]) {
+		muts = append(muts, mutation[T]{key: k, deleted: true})
+	})
+	for _, m := range muts {
+		c.deleteLocked(m.key)
+	}
+	c.writeBehindStageLocked(muts)
+	h := c.hooks
+	c.mu.Unlock()
+	c.notify(h, muts)
+	return len(muts)
+}
+
+// scanPrefixLocked calls fn for every stored entry whose key starts with
+// prefix, using the prefix index when available. The caller must hold c.mu.
+func (c *Cache[T]) scanPrefixLocked(prefix string, fn func(key string, e *entry[T])) {
+	if c.prefixIndex != nil {
+		for _, k := range c.prefixIndex.withPrefix(prefix) {
+			fn(k, c.data[k])
+		}
+		return
+	}
+	for k, e := range c.data {
+		if strings.HasPrefix(k, prefix) {
+			fn(k, e)
+		}
+	}
+}
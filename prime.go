@@ -0,0 +1,35 @@
+package cache
+
+// Prime replaces the contents with items without counting as a load:
+// LastLoaded stays as it was, so health and staleness checks still wait
+// for a real load, and OnReload hooks do not fire. The next successful
+// load replaces primed items like any others. items is copied. Items are
+// validated as a load's would be; if that fails the load under
+// WithInvalidPolicy, the contents are left unchanged.
+func (c *Cache[T]) Prime(items map[string]T) {
+	if c.rejectFrozen("prime") {
+		return
+	}
+	items, err := c.validItems(items)
+	if err != nil {
+		c.logf(LogErrorsOnly, "%s prime rejected: %v", c.label(), err)
+		return
+	}
+	data := c.loadEntries(items)
+	c.mu.Lock()
+	c.stripTombstonedLocked(data)
+	c.replaceLocked(data)
+	evicted := c.evictLocked()
+	h := c.hooks
+	c.mu.Unlock()
+	c.notify(h, evicted)
+	c.logf(LogDebug, "%s primed with %d items", c.label(), len(data))
+}
+
+// WithSeedData primes a new cache with items, as by Prime, so it can serve
+// reads before its first load.
+func WithSeedData[T any](items map[string]T) Option[T] {
+	return func(c *Cache[T]) {
+		c.seed = items
+	}
+}
@@ -0,0 +1,56 @@
+package cache
+
+// ReadOnlyCache is a read-only view of a Cache, for handing to code that
+// should only ever read it. It is backed by the cache's live data, so it
+// observes later Adds, Deletes and reloads; use Snapshot for a frozen view.
+type ReadOnlyCache[T any] struct {
+	c *Cache[T]
+}
+
+var _ ReadView[int] = (*ReadOnlyCache[int])(nil)
+
+// ReadOnly returns a read-only view of the cache.
+func (c *Cache[T]) ReadOnly() *ReadOnlyCache[T] {
+	return &ReadOnlyCache[T]{c: c}
+}
+
+// Get returns the item for a key, and a boolean indicating presence.
+func (r *ReadOnlyCache[T]) Get(key string) (T, bool) {
+	return r.c.Get(key)
+}
+
+// Has reports whether key holds a live item. See Cache.Has.
+func (r *ReadOnlyCache[T]) Has(key string) bool {
+	return r.c.Has(key)
+}
+
+// GetAll returns a shallow copy of the entire cached map.
+func (r *ReadOnlyCache[T]) GetAll() map[string]T {
+	return r.c.GetAll()
+}
+
+// Find returns all items satisfying the provided predicate.
+func (r *ReadOnlyCache[T]) Find(predicate func(T) bool) []T {
+	return r.c.Find(predicate)
+}
+
+// FindOne returns the first item satisfying predicate, or false if none.
+func (r *ReadOnlyCache[T]) FindOne(predicate func(T) bool) (T, bool) {
+	return r.c.FindOne(predicate)
+}
+
+// Len returns the number of live items in the cache.
+func (r *ReadOnlyCache[T]) Len() int {
+	return r.c.Len()
+}
+
+// Keys returns the keys of all live items, in no particular order.
+func (r *ReadOnlyCache[T]) Keys() []string {
+	return r.c.Keys()
+}
+
+// Range calls fn for each live item until fn returns false. See
+// Cache.Range.
+func (r *ReadOnlyCache[T]) Range(fn func(key string, value T) bool) {
+	r.c.Range(fn)
+}
@@ -0,0 +1,52 @@
+package cache
+
+import "math/rand/v2"
+
+// Sample returns up to n live items chosen uniformly at random, in random
+// order. If n exceeds the number of items, every item is returned.
+func (c *Cache[T]) Sample(n int) []T {
+	c.clearExpiredData(c.clock.Now())
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	keys := c.sampleKeysLocked(n)
+	values := make([]T, len(keys))
+	for i, k := range keys {
+		values[i] = c.output(c.data[k].val())
+	}
+	return values
+}
+
+// SampleKeys returns up to n keys of live items chosen uniformly at random,
+// in random order.
+func (c *Cache[T]) SampleKeys(n int) []string {
+	c.clearExpiredData(c.clock.Now())
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.sampleKeysLocked(n)
+}
+
+// sampleKeysLocked performs reservoir sampling over the live keys, using
+// O(n) memory regardless of the cache size. The caller must hold c.mu.
+func (c *Cache[T]) sampleKeysLocked(n int) []string {
+	if n <= 0 {
+		return nil
+	}
+	now := c.clock.Now()
+	reservoir := make([]string, 0, min(n, len(c.data)))
+	seen := 0
+	for k, e := range c.data {
+		if e.expired(now) {
+			continue
+		}
+		seen++
+		if len(reservoir) < n {
+			reservoir = append(reservoir, k)
+		} else if j := rand.IntN(seen); j < n {
+			reservoir[j] = k
+		}
+	}
+	rand.Shuffle(len(reservoir), func(i, j int) {
+		reservoir[i], reservoir[j] = reservoir[j], reservoir[i]
+	})
+	return reservoir
+}
@@ -0,0 +1,113 @@
+package cache
+
+import (
+	"math/rand/v2"
+	"time"
+)
+
+// defaultMinReloadDelay is the shortest wait ScheduleNextReload can cause
+// unless WithMinReloadDelay says otherwise.
+const defaultMinReloadDelay = time.Second
+
+// ScheduleNextReload makes the next automatic reload happen at t instead of
+// one interval after the previous one; later reloads fall back to the
+// interval unless scheduled again. It is typically called from an OnReload
+// hook, or from the loader itself, when the data carries its own expiry.
+// Times closer than the minimum reload delay (one second unless set by
+// WithMinReloadDelay), including times in the past, are pushed out to it.
+func (c *Cache[T]) ScheduleNextReload(t time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.nextAt = t
+	c.rescheduleLocked()
+}
+
+// WithSchedule reloads at the times chosen by next instead of at a fixed
+// interval: after each reload, and when auto-reload starts, the loop waits
+// until next(now). next can wrap a cron expression parser to reload, say,
+// at 02:00 and 14:00 on weekdays. The interval passed to NewCache is
+// ignored, and SetInterval fails with ErrScheduleSet. ScheduleNextReload
+// still overrides a single reload.
+func WithSchedule[T any](next func(after time.Time) time.Time) Option[T] {
+	return func(c *Cache[T]) {
+		c.schedule = next
+	}
+}
+
+// WithMinReloadDelay sets the shortest wait before an automatic reload that
+// ScheduleNextReload or a WithSchedule schedule can request, guarding
+// against hot reload loops.
+func WithMinReloadDelay[T any](d time.Duration) Option[T] {
+	return func(c *Cache[T]) {
+		c.minDelay = d
+	}
+}
+
+// WithStartDelay delays the first automatic reload after StartAutoReload by
+// d rather than a full interval.
+func WithStartDelay[T any](d time.Duration) Option[T] {
+	return func(c *Cache[T]) {
+		c.startDelay, c.startJitter = d, false
+	}
+}
+
+// WithRandomStartDelay delays the first automatic reload after
+// StartAutoReload by a random duration in [0, max), so that many caches
+// started together do not reload in lockstep.
+func WithRandomStartDelay[T any](max time.Duration) Option[T] {
+	return func(c *Cache[T]) {
+		c.startDelay, c.startJitter = max, true
+	}
+}
+
+// WithLoadOnStart makes StartAutoReload load once straight away, in the
+// auto-reload goroutine, independently of when the first scheduled reload
+// happens. With a start delay the cache thus fills immediately while its
+// periodic reloads stay spread out. It has no effect on a manual-only
+// cache.
+func WithLoadOnStart[T any](enabled bool) Option[T] {
+	return func(c *Cache[T]) {
+		c.loadOnStart = enabled
+	}
+}
+
+// firstReloadDelay returns the delay before the first automatic reload, or
+// 0 to wait as usual.
+func (c *Cache[T]) firstReloadDelay() time.Duration {
+	if c.startJitter && c.startDelay > 0 {
+		return rand.N(c.startDelay)
+	}
+	return c.startDelay
+}
+
+// rescheduleLocked wakes the reload loop, if running, to re-arm its timer.
+// The caller must hold c.mu.
+func (c *Cache[T]) rescheduleLocked() {
+	if c.run == nil {
+		return
+	}
+	select {
+	case c.run.resched <- struct{}{}:
+	default: // a wake-up is already pending
+	}
+}
+
+// nextReloadDelay returns how long the reload loop should wait before its
+// next reload.
+func (c *Cache[T]) nextReloadDelay() time.Duration {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.nextReloadDelayLocked()
+}
+
+// nextReloadDelayLocked is nextReloadDelay for callers holding c.mu.
+func (c *Cache[T]) nextReloadDelayLocked() time.Duration {
+	now := c.clock.Now()
+	switch {
+	case !c.nextAt.IsZero():
+		return max(c.nextAt.Sub(now), c.minDelay)
+	case c.schedule != nil:
+		return max(c.schedule(now).Sub(now), c.minDelay)
+	}
+	return c.interval
+}
@@ -0,0 +1,174 @@
+package cache_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/TheOrchestraX/cache"
+	"github.com/TheOrchestraX/cache/cachetest"
+)
+
+// recvLoad waits for the loader to signal on loads.
+func recvLoad(t *testing.T, loads <-chan struct{}) {
+	t.Helper()
+	select {
+	case <-loads:
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for a load")
+	}
+}
+
+func TestLoadOnStartWithStartDelay(t *testing.T) {
+	clk := cachetest.NewFakeClock(epoch)
+	loads := make(chan struct{}, 10)
+	c := cache.NewCache(func() (map[string]int, error) {
+		loads <- struct{}{}
+		return map[string]int{"a": 1}, nil
+	}, time.Hour,
+		cache.WithClock[int](clk),
+		cache.WithLogLevel[int](cache.LogSilent),
+		cache.WithStartDelay[int](10*time.Minute),
+		cache.WithLoadOnStart[int](true))
+	if !c.Config().LoadOnStart {
+		t.Fatal("Config().LoadOnStart = false")
+	}
+	c.StartAutoReload()
+	defer c.StopAutoReload()
+
+	recvLoad(t, loads) // the immediate load, before any time passes
+	clk.Advance(10 * time.Minute)
+	recvLoad(t, loads) // the delayed first reload
+	select {
+	case <-loads:
+		t.Fatal("unexpected extra load")
+	default:
+	}
+}
+
+// loadTimes returns a cache on a fake clock whose loader sends the clock's
+// time on each load.
+func loadTimes(t *testing.T, interval time.Duration, opts ...cache.Option[int]) (*cache.Cache[int], *cachetest.FakeClock, <-chan time.Time) {
+	t.Helper()
+	clk := cachetest.NewFakeClock(epoch)
+	loads := make(chan time.Time, 10)
+	opts = append([]cache.Option[int]{cache.WithClock[int](clk), cache.WithLogLevel[int](cache.LogSilent)}, opts...)
+	c := cache.NewCache(func() (map[string]int, error) {
+		loads <- clk.Now()
+		return map[string]int{"a": 1}, nil
+	}, interval, opts...)
+	return c, clk, loads
+}
+
+// expectLoadAt advances the clock to at, checking that no load happens
+// before it and that one happens there.
+func expectLoadAt(t *testing.T, clk *cachetest.FakeClock, loads <-chan time.Time, at time.Time) {
+	t.Helper()
+	waitFor(t, "the reload timer", func() bool { return clk.Waiters() > 0 })
+	clk.Set(at.Add(-time.Second))
+	select {
+	case got := <-loads:
+		t.Fatalf("load at %s, want none before %s", got, at)
+	default:
+	}
+	clk.Set(at)
+	select {
+	case got := <-loads:
+		if !got.Equal(at) {
+			t.Fatalf("load at %s, want %s", got, at)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatalf("no load at %s", at)
+	}
+}
+
+func TestScheduleFiresAndReschedules(t *testing.T) {
+	quarterHours := func(after time.Time) time.Time {
+		return after.Truncate(15 * time.Minute).Add(15 * time.Minute)
+	}
+	c, clk, loads := loadTimes(t, time.Hour, cache.WithSchedule[int](quarterHours))
+	if err := c.SetInterval(time.Minute); !errors.Is(err, cache.ErrScheduleSet) {
+		t.Fatalf("SetInterval = %v, want ErrScheduleSet", err)
+	}
+	clk.Advance(5 * time.Minute)
+	c.StartAutoReload()
+	defer c.StopAutoReload()
+
+	expectLoadAt(t, clk, loads, epoch.Add(15*time.Minute))
+	expectLoadAt(t, clk, loads, epoch.Add(30*time.Minute))
+	expectLoadAt(t, clk, loads, epoch.Add(45*time.Minute))
+}
+
+func TestScheduleNextReloadOverridesOnce(t *testing.T) {
+	c, clk, loads := loadTimes(t, time.Hour)
+	c.ScheduleNextReload(epoch.Add(10 * time.Minute))
+	c.StartAutoReload()
+	defer c.StopAutoReload()
+
+	expectLoadAt(t, clk, loads, epoch.Add(10*time.Minute))
+	// Then back to the interval.
+	expectLoadAt(t, clk, loads, epoch.Add(70*time.Minute))
+}
+
+func TestScheduleNextReloadInThePast(t *testing.T) {
+	c, clk, loads := loadTimes(t, time.Hour, cache.WithMinReloadDelay[int](time.Minute))
+	clk.Advance(5 * time.Minute)
+	c.ScheduleNextReload(epoch)
+	c.StartAutoReload()
+	defer c.StopAutoReload()
+
+	// Pushed out to the minimum delay.
+	expectLoadAt(t, clk, loads, epoch.Add(6*time.Minute))
+}
+
+func TestNonPositiveIntervalIsManualOnly(t *testing.T) {
+	for _, interval := range []time.Duration{0, -time.Minute} {
+		c, clk, loads := loadTimes(t, interval, cache.WithJanitorInterval[int](time.Minute))
+		c.StartAutoReload()
+		if c.IsAutoReloading() {
+			t.Fatalf("interval %s: IsAutoReloading = true", interval)
+		}
+		// Only the janitor was started.
+		waitFor(t, "the janitor", func() bool { return clk.Waiters() == 1 })
+		clk.Advance(time.Hour)
+		c.StopAutoReload()
+		select {
+		case <-loads:
+			t.Fatalf("interval %s: a manual-only cache reloaded by itself", interval)
+		default:
+		}
+	}
+}
+
+func TestSetIntervalNonPositiveStopsAutoReload(t *testing.T) {
+	c, clk, loads := loadTimes(t, time.Minute)
+	c.StartAutoReload()
+	defer c.StopAutoReload()
+	expectLoadAt(t, clk, loads, epoch.Add(time.Minute))
+
+	if err := c.SetInterval(0); err != nil {
+		t.Fatal(err)
+	}
+	if c.IsAutoReloading() || c.Config().Interval != 0 {
+		t.Fatal("SetInterval(0) left auto-reload running")
+	}
+	c.StartAutoReload() // does nothing while manual-only
+	if c.IsAutoReloading() {
+		t.Fatal("StartAutoReload started a manual-only cache")
+	}
+	clk.Advance(time.Hour)
+	select {
+	case got := <-loads:
+		t.Fatalf("load at %s after SetInterval(0)", got)
+	default:
+	}
+
+	if err := c.SetInterval(time.Minute); err != nil {
+		t.Fatal(err)
+	}
+	c.StartAutoReload()
+	if !c.IsAutoReloading() {
+		t.Fatal("auto-reload did not start once given an interval")
+	}
+	expectLoadAt(t, clk, loads, clk.Now().Add(time.Minute))
+}
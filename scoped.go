@@ -0,0 +1,111 @@
+package cache
+
+import "strings"
+
+// ScopedCache is a view of the items of a Cache whose keys start with a
+// prefix, for handing each module its own namespace inside a shared cache.
+// Keys passed to and returned from its methods omit the prefix, so a
+// module cannot reach items outside its scope. Like ReadOnlyCache it is
+// backed by the cache's live data and observes reloads of the parent.
+type ScopedCache[T any] struct {
+	c      *Cache[T]
+	prefix string
+}
+
+// Scoped returns a view of the items whose keys start with prefix. Under
+// WithKeyNormalizer the prefix is normalized as a key would be, so it
+// matches the keys stored through the view.
+func (c *Cache[T]) Scoped(prefix string) *ScopedCache[T] {
+	return &ScopedCache[T]{c: c, prefix: c.normKey(prefix)}
+}
+
+// Scoped returns a view nested inside s, for keys starting with s's prefix
+// followed by prefix.
+func (s *ScopedCache[T]) Scoped(prefix string) *ScopedCache[T] {
+	return s.c.Scoped(s.prefix + prefix)
+}
+
+// Prefix returns the prefix the view adds to keys.
+func (s *ScopedCache[T]) Prefix() string {
+	return s.prefix
+}
+
+// Get returns the item for a key, and a boolean indicating presence. Under
+// WithAliases an alias resolving to an item outside the scope misses.
+func (s *ScopedCache[T]) Get(key string) (T, bool) {
+	return s.c.get(s.prefix+key, s.prefix)
+}
+
+// Add inserts or updates a single item.
+func (s *ScopedCache[T]) Add(key string, value T) {
+	s.c.Add(s.prefix+key, value)
+}
+
+// Delete removes the item with the given key. Like Get it does not follow
+// aliases out of the scope.
+func (s *ScopedCache[T]) Delete(key string) {
+	s.c.remove(s.prefix+key, s.prefix)
+}
+
+// Keys returns the keys of all live items in scope, in no particular order.
+func (s *ScopedCache[T]) Keys() []string {
+	keys := s.c.KeysWithPrefix(s.prefix)
+	for i, k := range keys {
+		keys[i] = strings.TrimPrefix(k, s.prefix)
+	}
+	return keys
+}
+
+// Len returns the number of live items in scope.
+func (s *ScopedCache[T]) Len() int {
+	n := 0
+	s.Range(func(string, T) bool {
+		n++
+		return true
+	})
+	return n
+}
+
+// GetAll returns a copy of the items in scope.
+func (s *ScopedCache[T]) GetAll() map[string]T {
+	result := make(map[string]T)
+	s.Range(func(key string, value T) bool {
+		result[key] = value
+		return true
+	})
+	return result
+}
+
+// Find returns all items in scope satisfying the provided predicate.
+func (s *ScopedCache[T]) Find(predicate func(T) bool) []T {
+	var results []T
+	s.Range(func(_ string, value T) bool {
+		if predicate(value) {
+			results = append(results, value)
+		}
+		return true
+	})
+	return results
+}
+
+// Range calls fn for every live item in scope until fn returns false. Like
+// Cache.Range it holds the read lock throughout, so fn must not modify the
+// cache.
+func (s *ScopedCache[T]) Range(fn func(key string, value T) bool) {
+	now := s.c.clock.Now()
+	s.c.mu.RLock()
+	defer s.c.mu.RUnlock()
+	done := false
+	s.c.scanPrefixLocked(s.prefix, func(k string, e *entry[T]) {
+		if done || e.expired(now) {
+			return
+		}
+		done = !fn(strings.TrimPrefix(k, s.prefix), s.c.output(e.val()))
+	})
+}
+
+// Clear removes every item in scope, leaving the rest of the cache alone,
+// and returns how many were removed.
+func (s *ScopedCache[T]) Clear() int {
+	return s.c.DeleteByPrefix(s.prefix)
+}
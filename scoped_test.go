@@ -0,0 +1,100 @@
+package cache_test
+
+import (
+	"maps"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/TheOrchestraX/cache"
+)
+
+func TestScopedIsolation(t *testing.T) {
+	c, _ := newTestCache(t, map[string]int{"shared": 0})
+	a, b := c.Scoped("a:"), c.Scoped("b:")
+	a.Add("x", 1)
+	a.Add("y", 2)
+	b.Add("x", 10)
+
+	if v, ok := a.Get("x"); !ok || v != 1 {
+		t.Fatalf("a.Get(x) = %d, %v; want 1", v, ok)
+	}
+	if v, ok := b.Get("x"); !ok || v != 10 {
+		t.Fatalf("b.Get(x) = %d, %v; want 10", v, ok)
+	}
+	if _, ok := b.Get("y"); ok {
+		t.Fatal("b sees a's item y")
+	}
+	if got, want := a.GetAll(), map[string]int{"x": 1, "y": 2}; !maps.Equal(got, want) {
+		t.Fatalf("a.GetAll = %v, want %v", got, want)
+	}
+	if got := b.Keys(); !slices.Equal(got, []string{"x"}) {
+		t.Fatalf("b.Keys = %v, want [x]", got)
+	}
+	if n := b.Len(); n != 1 {
+		t.Fatalf("b.Len = %d, want 1", n)
+	}
+
+	b.Delete("y") // a's y is out of b's reach
+	mustGet(t, c, "a:y")
+	if n := a.Clear(); n != 2 {
+		t.Fatalf("a.Clear removed %d items, want 2", n)
+	}
+	if got, want := c.GetAll(), map[string]int{"shared": 0, "b:x": 10}; !maps.Equal(got, want) {
+		t.Fatalf("GetAll after a.Clear = %v, want %v", got, want)
+	}
+
+	nested := c.Scoped("b:").Scoped("n:")
+	nested.Add("z", 3)
+	if got := b.Keys(); !slices.Contains(got, "n:z") {
+		t.Fatalf("b.Keys = %v, want it to include the nested n:z", got)
+	}
+}
+
+func TestScopedKeyNormalizer(t *testing.T) {
+	c, _ := newTestCache(t, map[string]int{}, cache.WithKeyNormalizer[int](strings.ToLower))
+	s := c.Scoped("Tenant:")
+	if p := s.Prefix(); p != "tenant:" {
+		t.Fatalf("Prefix = %q, want the normalized tenant:", p)
+	}
+	s.Add("X", 1)
+	s.Scoped("Sub:").Add("Y", 2)
+	if v, ok := s.Get("x"); !ok || v != 1 {
+		t.Fatalf("Get(x) = %d, %v; want 1", v, ok)
+	}
+	keys := s.Keys()
+	slices.Sort(keys)
+	if want := []string{"sub:y", "x"}; !slices.Equal(keys, want) {
+		t.Fatalf("Keys = %v, want %v", keys, want)
+	}
+	if n := s.Len(); n != 2 {
+		t.Fatalf("Len = %d, want 2", n)
+	}
+	if got := s.GetAll(); len(got) != 2 {
+		t.Fatalf("GetAll = %v, want 2 items", got)
+	}
+}
+
+func TestScopedAliasesStayInScope(t *testing.T) {
+	type user struct{ Email string }
+	c, _ := newTestCache(t, map[string]user{
+		"a:1": {Email: "a:alice"},
+		// b's item claims an alias inside a's scope.
+		"b:1": {Email: "a:bob"},
+	}, cache.WithAliases(func(u user) []string { return []string{u.Email} }))
+	a, b := c.Scoped("a:"), c.Scoped("b:")
+
+	if v, ok := a.Get("alice"); !ok || v.Email != "a:alice" {
+		t.Fatalf("a.Get(alice) = %+v, %v; want a's own item through its alias", v, ok)
+	}
+	if v, ok := a.Get("bob"); ok {
+		t.Fatalf("a.Get(bob) = %+v through an alias of b's item, want a miss", v)
+	}
+	a.Delete("bob")
+	mustGet(t, c, "b:1")
+	if _, ok := b.Get("1"); !ok {
+		t.Fatal("b lost its item")
+	}
+	// The whole cache still follows the alias.
+	mustGet(t, c, "a:bob")
+}
@@ -0,0 +1,216 @@
+package cache
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// maxShadowExamples bounds the example keys in a ShadowReport.
+const maxShadowExamples = 10
+
+// ShadowReport is the outcome of comparing one primary load against the
+// shadow loader's result.
+type ShadowReport struct {
+	// Compared is the number of keys both loaders produced.
+	Compared int
+	// Mismatched counts keys whose values compare unequal.
+	Mismatched int
+	// MissingInShadow and MissingInPrimary count keys only one loader
+	// produced.
+	MissingInShadow  int
+	MissingInPrimary int
+	// Examples holds up to 10 of the disagreeing keys.
+	Examples []string
+	// Err is the shadow loader's error, in which case nothing was compared.
+	Err error
+}
+
+// Agree reports whether the loaders produced the same data.
+func (r ShadowReport) Agree() bool {
+	return r.Err == nil && r.Mismatched == 0 && r.MissingInShadow == 0 && r.MissingInPrimary == 0
+}
+
+// shadowLoader is the WithShadowLoader configuration.
+type shadowLoader[T any] struct {
+	load    func() (map[string]T, error)
+	compare func(key string, a, b T) bool
+}
+
+// WithShadowLoader runs fn alongside every successful load as a dry run of
+// a replacement data source, comparing its result key by key using
+// compare (reflect.DeepEqual when nil) and reporting to OnShadowReport
+// hooks and Stats. Only the primary loader's data is ever installed, and
+// the comparison happens in the background, so a slow or failing shadow
+// never delays or fails the primary load. While a shadow run is still in
+// progress, later loads skip theirs. The comparison reads a Snapshot of the
+// installed data, so the next write after each load copies the map. The
+// shadow's result is first transformed, validated, key-normalized and
+// filtered for tombstones and pins as the primary's was, so only genuine
+// differences between the sources are reported.
+func WithShadowLoader[T any](fn func() (map[string]T, error), compare func(key string, a, b T) bool) Option[T] {
+	return func(c *Cache[T]) {
+		if compare == nil {
+			compare = func(_ string, a, b T) bool { return reflect.DeepEqual(a, b) }
+		}
+		c.shadow = &shadowLoader[T]{load: fn, compare: compare}
+	}
+}
+
+// OnShadowReport registers fn to be called with the outcome of each
+// WithShadowLoader comparison, whether or not the loaders agreed. fn runs
+// on the comparison goroutine, outside the cache lock.
+func (c *Cache[T]) OnShadowReport(fn func(ShadowReport)) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.hooks.onShadowReport = append(c.hooks.onShadowReport, fn)
+}
+
+// startShadow runs the shadow loader in the background, unless a previous
+// run is still in progress, and returns the channel its result arrives on,
+// or nil if it was not started.
+func (c *Cache[T]) startShadow() chan shadowResult[T] {
+	if c.shadow == nil || !c.shadowBusy.CompareAndSwap(false, true) {
+		return nil
+	}
+	ch := make(chan shadowResult[T], 1)
+	go func() {
+		var r shadowResult[T]
+		defer func() {
+			if p := recover(); p != nil {
+				r.err = fmt.Errorf("%w: %v", ErrLoaderPanic, p)
+			}
+			ch <- r
+		}()
+		r.data, r.err = c.shadow.load()
+	}()
+	return ch
+}
+
+// shadowResult is what the shadow loader returned.
+type shadowResult[T any] struct {
+	data map[string]T
+	err  error
+}
+
+// finishShadow handles a shadow run started by startShadow once the
+// primary load has returned primaryErr. After a successful load it waits
+// in the background for the shadow and compares its result with the data
+// just installed; otherwise the shadow result is discarded.
+func (c *Cache[T]) finishShadow(ch chan shadowResult[T], primaryErr error) {
+	if ch == nil {
+		return
+	}
+	if primaryErr != nil {
+		go func() {
+			<-ch
+			c.shadowBusy.Store(false)
+		}()
+		return
+	}
+	primary := c.Snapshot()
+	go func() {
+		defer c.shadowBusy.Store(false)
+		c.reportShadow(c.compareShadow(primary, <-ch))
+	}()
+}
+
+// prepareShadow puts the shadow's result through the steps a primary
+// load's data goes through before it is installed, so that it can be
+// compared with the installed data like for like: values are transformed
+// and validated, failing the run under FailLoad; keys are normalized;
+// tombstoned keys are dropped; and pinned keys the shadow lacks take the
+// primary's entry, as a reload keeps them. Rejections are not counted in
+// Stats.Invalid.
+func (c *Cache[T]) prepareShadow(primary *Snapshot[T], items map[string]T) (map[string]T, error) {
+	valid := make(map[string]T, len(items))
+	for k, v := range items {
+		v, err := c.check(k, v)
+		switch {
+		case err == nil:
+			valid[k] = v
+		case c.invalidPolicy == FailLoad:
+			return nil, err
+		}
+	}
+	valid = c.normalizeItems(valid)
+	now := c.clock.Now().UnixNano()
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	for k := range valid {
+		if c.tombstonedLocked(k, now) {
+			delete(valid, k)
+		}
+	}
+	for k := range c.pinned {
+		if _, ok := valid[k]; ok {
+			continue
+		}
+		if e, ok := primary.data[k]; ok && !e.expired(primary.at) {
+			valid[k] = e.val()
+		}
+	}
+	return valid, nil
+}
+
+// compareShadow compares the primary's installed data with the shadow's
+// result, as prepared by prepareShadow.
+func (c *Cache[T]) compareShadow(primary *Snapshot[T], shadow shadowResult[T]) ShadowReport {
+	var r ShadowReport
+	if shadow.err == nil {
+		shadow.data, shadow.err = c.prepareShadow(primary, shadow.data)
+	}
+	if shadow.err != nil {
+		r.Err = shadow.err
+		return r
+	}
+	example := func(key string) {
+		if len(r.Examples) < maxShadowExamples {
+			r.Examples = append(r.Examples, key)
+		}
+	}
+	for k, e := range primary.data {
+		if e.expired(primary.at) {
+			continue
+		}
+		b, ok := shadow.data[k]
+		switch {
+		case !ok:
+			r.MissingInShadow++
+			example(k)
+		case !c.shadow.compare(k, e.val(), b):
+			r.Compared++
+			r.Mismatched++
+			example(k)
+		default:
+			r.Compared++
+		}
+	}
+	for k := range shadow.data {
+		if _, ok := primary.Get(k); !ok {
+			r.MissingInPrimary++
+			example(k)
+		}
+	}
+	return r
+}
+
+// reportShadow records r in Stats, logs disagreements and fires the
+// OnShadowReport hooks.
+func (c *Cache[T]) reportShadow(r ShadowReport) {
+	c.stats.shadowRuns.Add(1)
+	switch {
+	case r.Err != nil:
+		c.stats.shadowErrors.Add(1)
+		c.logf(LogErrorsOnly, "%s shadow load error: %v", c.label(), r.Err)
+	case !r.Agree():
+		c.stats.shadowMismatches.Add(uint64(r.Mismatched + r.MissingInShadow + r.MissingInPrimary))
+		c.logf(LogInfo, "%s shadow load disagrees: %d mismatched, %d missing in shadow, %d missing in primary (e.g. %q)",
+			c.label(), r.Mismatched, r.MissingInShadow, r.MissingInPrimary, r.Examples)
+	}
+	c.mu.RLock()
+	h := c.hooks
+	c.mu.RUnlock()
+	for _, fn := range h.onShadowReport {
+		fn(r)
+	}
+}
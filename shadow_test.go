@@ -0,0 +1,73 @@
+package cache_test
+
+import (
+	"maps"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/TheOrchestraX/cache"
+	"github.com/TheOrchestraX/cache/cachetest"
+)
+
+// newShadowCache is newTestCache without the initial load, whose shadow
+// run would race with the test's.
+func newShadowCache(t *testing.T, data map[string]int, opts ...cache.Option[int]) *cache.Cache[int] {
+	t.Helper()
+	opts = append([]cache.Option[int]{cache.WithClock[int](cachetest.NewFakeClock(epoch)), cache.WithLogLevel[int](cache.LogSilent)}, opts...)
+	return cache.NewCache(func() (map[string]int, error) { return maps.Clone(data), nil }, 0, opts...)
+}
+
+// shadowReport loads c and returns the report of the shadow run.
+func shadowReport(t *testing.T, c *cache.Cache[int], reports <-chan cache.ShadowReport) cache.ShadowReport {
+	t.Helper()
+	if err := c.Load(); err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	select {
+	case r := <-reports:
+		return r
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for a shadow report")
+		return cache.ShadowReport{}
+	}
+}
+
+func TestShadowComparesThroughPipeline(t *testing.T) {
+	primary := map[string]int{"A": 1, "b": 2, "gone": 3, "bad": -1}
+	reports := make(chan cache.ShadowReport, 1)
+	c := newShadowCache(t, primary,
+		cache.WithKeyNormalizer[int](strings.ToLower),
+		cache.WithTransform(func(key string, v int) (int, error) { return 10 * v, nil }),
+		cache.WithValueValidator(nonNegative),
+		cache.WithShadowLoader(func() (map[string]int, error) { return maps.Clone(primary), nil }, nil))
+	c.OnShadowReport(func(r cache.ShadowReport) { reports <- r })
+	c.DeleteWithTombstone("gone", time.Hour)
+	c.Pin("b")
+	if r := shadowReport(t, c, reports); !r.Agree() {
+		t.Fatalf("identical sources disagree: %+v", r)
+	}
+	if n := c.Stats().Invalid; n != 1 {
+		t.Fatalf("Stats().Invalid = %d, want 1, from the primary load only", n)
+	}
+	delete(primary, "b") // the pinned key stays, in both
+	if r := shadowReport(t, c, reports); !r.Agree() {
+		t.Fatalf("sources without the pinned key disagree: %+v", r)
+	}
+}
+
+func TestShadowReportsDifferences(t *testing.T) {
+	reports := make(chan cache.ShadowReport, 1)
+	c := newShadowCache(t, map[string]int{"a": 1, "b": 2},
+		cache.WithShadowLoader(func() (map[string]int, error) {
+			return map[string]int{"a": 1, "b": 3, "c": 4}, nil
+		}, nil))
+	c.OnShadowReport(func(r cache.ShadowReport) { reports <- r })
+	r := shadowReport(t, c, reports)
+	if r.Compared != 2 || r.Mismatched != 1 || r.MissingInPrimary != 1 || r.MissingInShadow != 0 {
+		t.Fatalf("report = %+v, want 2 compared, 1 mismatched, 1 missing in primary", r)
+	}
+	if s := c.Stats(); s.ShadowRuns == 0 || s.ShadowMismatches == 0 {
+		t.Fatalf("Stats = %+v, want shadow runs and mismatches counted", s)
+	}
+}
@@ -0,0 +1,62 @@
+package cache
+
+// OnSizeChange registers fn to be called with the new item count whenever
+// it changes, whether through Add, Delete, Clear, Load or any other
+// mutation. Reports are coalesced: concurrent mutations may produce a
+// single call with the final count, and a call is skipped when the count
+// ends up unchanged. The count includes expired items not yet removed.
+// fn runs outside the cache lock and may call back into the cache.
+func (c *Cache[T]) OnSizeChange(fn func(n int)) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.hooks.onSizeChange = append(c.hooks.onSizeChange, fn)
+	c.sizeWatched.Store(true)
+}
+
+// WithGaugeFunc hands the cache's core gauges to fn, for metrics systems
+// that are fed by callbacks. fn is called outside the cache lock with
+// "items" whenever the item count changes (as for OnSizeChange) and with
+// "consecutive_failures" after every load attempt. The gauge names are
+// the same for every cache; fn can label them with the cache's Name.
+func WithGaugeFunc[T any](fn func(name string, value float64)) Option[T] {
+	return func(c *Cache[T]) {
+		c.gaugeFn = fn
+		c.sizeWatched.Store(fn != nil)
+	}
+}
+
+// gauge reports a gauge value to the WithGaugeFunc callback, if any.
+func (c *Cache[T]) gauge(name string, value float64) {
+	if c.gaugeFn != nil {
+		c.gaugeFn(name, value)
+	}
+}
+
+// sizeChanged reports the current item count to OnSizeChange hooks and the
+// gauge func if it differs from the last count reported. It must be called
+// without holding c.mu. Only one goroutine reports at a time; a call that
+// finds a report in progress, including one made re-entrantly from a hook,
+// just marks the size dirty and leaves the reporter to pick it up.
+func (c *Cache[T]) sizeChanged() {
+	if !c.sizeWatched.Load() {
+		return
+	}
+	c.sizeDirty.Store(true)
+	for c.sizeDirty.Load() && c.sizeMu.TryLock() {
+		for c.sizeDirty.Swap(false) {
+			c.mu.RLock()
+			n := len(c.data)
+			fns := c.hooks.onSizeChange
+			c.mu.RUnlock()
+			if n == c.reportedSize {
+				continue
+			}
+			c.reportedSize = n
+			for _, fn := range fns {
+				fn(n)
+			}
+			c.gauge("items", float64(n))
+		}
+		c.sizeMu.Unlock()
+	}
+}
@@ -0,0 +1,49 @@
+package cache
+
+import (
+	"fmt"
+	"time"
+)
+
+// NewCacheFromSlice constructs a Cache whose loader returns a slice, keying
+// each element with keyFn. Duplicate keys resolve to the last element; use
+// NewCache with UniqueSliceLoader to reject them instead.
+func NewCacheFromSlice[T any](load func() ([]T, error), keyFn func(T) string, interval time.Duration, opts ...Option[T]) *Cache[T] {
+	return NewCache(SliceLoader(load, keyFn), interval, opts...)
+}
+
+// SliceLoader adapts a slice-returning load function into a loader keyed by
+// keyFn. When several elements share a key, the last one wins.
+func SliceLoader[T any](load func() ([]T, error), keyFn func(T) string) func() (map[string]T, error) {
+	return func() (map[string]T, error) {
+		items, err := load()
+		if err != nil {
+			return nil, err
+		}
+		result := make(map[string]T, len(items))
+		for _, item := range items {
+			result[keyFn(item)] = item
+		}
+		return result, nil
+	}
+}
+
+// UniqueSliceLoader is like SliceLoader but fails the load with an error
+// wrapping ErrDuplicateKey when two elements share a key.
+func UniqueSliceLoader[T any](load func() ([]T, error), keyFn func(T) string) func() (map[string]T, error) {
+	return func() (map[string]T, error) {
+		items, err := load()
+		if err != nil {
+			return nil, err
+		}
+		result := make(map[string]T, len(items))
+		for i, item := range items {
+			key := keyFn(item)
+			if _, dup := result[key]; dup {
+				return nil, fmt.Errorf("%w: %q at index %d", ErrDuplicateKey, key, i)
+			}
+			result[key] = item
+		}
+		return result, nil
+	}
+}
@@ -0,0 +1,94 @@
+package cache
+
+import "time"
+
+// reloadHistorySize is the number of recent load durations kept for the
+// rolling average.
+const reloadHistorySize = 16
+
+// minSlowReloadSamples is the number of durations needed before
+// WithSlowReloadFactor judges a load against the rolling average.
+const minSlowReloadSamples = 3
+
+// reloadHistory is a ring of recent load durations, guarded by Cache.mu.
+type reloadHistory struct {
+	took [reloadHistorySize]time.Duration
+	next int
+	n    int
+}
+
+// add records a load that took d, displacing the oldest when full.
+func (r *reloadHistory) add(d time.Duration) {
+	r.took[r.next] = d
+	r.next = (r.next + 1) % len(r.took)
+	r.n = min(r.n+1, len(r.took))
+}
+
+// last returns the most recent duration, or 0 if there is none.
+func (r *reloadHistory) last() time.Duration {
+	if r.n == 0 {
+		return 0
+	}
+	return r.took[(r.next+len(r.took)-1)%len(r.took)]
+}
+
+// mean returns the average of the recorded durations, or 0 if there are
+// none.
+func (r *reloadHistory) mean() time.Duration {
+	if r.n == 0 {
+		return 0
+	}
+	var sum time.Duration
+	for _, d := range r.took[:r.n] {
+		sum += d
+	}
+	return sum / time.Duration(r.n)
+}
+
+// WithSlowReloadThreshold flags loads that take longer than d: a warning is
+// logged at LogErrorsOnly and OnSlowReload hooks fire, whether or not the
+// load then succeeds.
+func WithSlowReloadThreshold[T any](d time.Duration) Option[T] {
+	return func(c *Cache[T]) {
+		c.slowAfter = d
+	}
+}
+
+// WithSlowReloadFactor flags loads that take longer than factor times the
+// rolling average of recent loads, catching a loader that is degrading
+// relative to its own history. It takes effect once a few loads have been
+// recorded, and combines with WithSlowReloadThreshold.
+func WithSlowReloadFactor[T any](factor float64) Option[T] {
+	return func(c *Cache[T]) {
+		c.slowFactor = factor
+	}
+}
+
+// OnSlowReload registers fn to be called after a load is flagged as slow by
+// WithSlowReloadThreshold or WithSlowReloadFactor, with its duration and
+// the rolling average of the loads before it. fn runs outside the cache
+// lock and may call back into the cache.
+func (c *Cache[T]) OnSlowReload(fn func(took, average time.Duration)) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.hooks.onSlowReload = append(c.hooks.onSlowReload, fn)
+}
+
+// observeReload records a load, successful or not, that took took, and
+// reports it if it was slow.
+func (c *Cache[T]) observeReload(took time.Duration) {
+	c.mu.Lock()
+	avg := c.reloadTimes.mean()
+	slow := c.slowAfter > 0 && took > c.slowAfter ||
+		c.slowFactor > 0 && c.reloadTimes.n >= minSlowReloadSamples && float64(took) > c.slowFactor*float64(avg)
+	c.reloadTimes.add(took)
+	h := c.hooks
+	c.mu.Unlock()
+	if !slow {
+		return
+	}
+	c.logf(LogErrorsOnly, "%s slow load: took %s (average %s)", c.label(), took, avg)
+	for _, fn := range h.onSlowReload {
+		fn(took, avg)
+	}
+}
@@ -0,0 +1,120 @@
+package cache
+
+import "time"
+
+// Snapshot is a frozen, read-only view of the cache contents at the moment
+// Snapshot was called. Adds, Deletes and reloads made afterwards are not
+// visible through it. Expiry is evaluated as of the capture time, so items
+// live when the snapshot was taken remain visible.
+type Snapshot[T any] struct {
+	data  map[string]*entry[T]
+	at    time.Time
+	gen   uint64
+	clone func(T) T
+	keys  []string // live keys in order, under WithStableIteration
+}
+
+// Snapshot captures the current contents without copying them. The cache
+// switches to copy-on-write, so the first mutation after a snapshot pays for
+// one map copy while readers of the snapshot are never blocked.
+func (c *Cache[T]) Snapshot() *Snapshot[T] {
+	c.clearExpiredData(c.clock.Now())
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.snapshotLocked()
+}
+
+// snapshotLocked implements Snapshot. The caller must hold c.mu.
+func (c *Cache[T]) snapshotLocked() *Snapshot[T] {
+	c.shared.Store(true)
+	s := &Snapshot[T]{data: c.data, at: c.clock.Now(), gen: c.generation.Load(), clone: c.cloner}
+	if c.stable {
+		s.keys = c.sortedKeysLocked(s.at)
+	}
+	return s
+}
+
+// Get returns the item for a key as of the snapshot.
+func (s *Snapshot[T]) Get(key string) (T, bool) {
+	e, ok := s.data[key]
+	if !ok || e.expired(s.at) {
+		var zero T
+		return zero, false
+	}
+	return s.output(e.val()), true
+}
+
+// Range calls fn for every item in the snapshot until fn returns false.
+// Iteration order is unspecified unless the cache has WithStableIteration.
+func (s *Snapshot[T]) Range(fn func(key string, value T) bool) {
+	s.each(func(k string, e *entry[T]) bool {
+		return fn(k, s.output(e.val()))
+	})
+}
+
+// each calls fn for each live entry until fn returns false, in key order
+// if the snapshot has one.
+func (s *Snapshot[T]) each(fn func(key string, e *entry[T]) bool) {
+	if s.keys != nil {
+		for _, k := range s.keys {
+			if !fn(k, s.data[k]) {
+				return
+			}
+		}
+		return
+	}
+	for k, e := range s.data {
+		if !e.expired(s.at) && !fn(k, e) {
+			return
+		}
+	}
+}
+
+// Find returns all items in the snapshot satisfying predicate.
+func (s *Snapshot[T]) Find(predicate func(T) bool) []T {
+	var results []T
+	s.Range(func(_ string, v T) bool {
+		if predicate(v) {
+			results = append(results, v)
+		}
+		return true
+	})
+	return results
+}
+
+// FindSeq returns the items in the snapshot satisfying predicate as a
+// sequence of key-value pairs, in the shape of an iter.Seq2[string, T].
+// Matches are produced one at a time, so the consumer can stop early and
+// the result is never held in memory as a whole.
+func (s *Snapshot[T]) FindSeq(predicate func(T) bool) func(yield func(key string, value T) bool) {
+	return func(yield func(string, T) bool) {
+		s.each(func(k string, e *entry[T]) bool {
+			v := e.val()
+			return !predicate(v) || yield(k, s.output(v))
+		})
+	}
+}
+
+// Len returns the number of items in the snapshot.
+func (s *Snapshot[T]) Len() int {
+	n := 0
+	for _, e := range s.data {
+		if !e.expired(s.at) {
+			n++
+		}
+	}
+	return n
+}
+
+// Generation returns the cache's Generation when the snapshot was taken.
+func (s *Snapshot[T]) Generation() uint64 {
+	return s.gen
+}
+
+// output applies the cache's cloner, if any, to a value being returned.
+func (s *Snapshot[T]) output(v T) T {
+	if s.clone != nil {
+		return s.clone(v)
+	}
+	return v
+}
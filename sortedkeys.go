@@ -0,0 +1,48 @@
+package cache
+
+import (
+	"slices"
+	"time"
+)
+
+// SortedKeys returns the keys of all live items in ascending order. The
+// sorted order is cached and only recomputed after the contents change, so
+// repeated calls on an unchanged cache cost a copy rather than a sort. The
+// returned slice belongs to the caller.
+func (c *Cache[T]) SortedKeys() []string {
+	now := c.clock.Now()
+	c.clearExpiredData(now)
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.sortedKeysLocked(now)
+}
+
+// sortedKeysLocked returns a fresh slice of the live keys in ascending
+// order, reusing the cached order when the generation is unchanged. The
+// caller must hold c.mu.
+func (c *Cache[T]) sortedKeysLocked(now time.Time) []string {
+	var keys []string
+	if c.prefixIndex != nil {
+		keys = c.prefixIndex.keys
+	} else {
+		gen := c.generation.Load()
+		c.sortedMu.Lock()
+		if c.sorted == nil || c.sortedGen != gen {
+			c.sorted = make([]string, 0, len(c.data))
+			for k := range c.data {
+				c.sorted = append(c.sorted, k)
+			}
+			slices.Sort(c.sorted)
+			c.sortedGen = gen
+		}
+		keys = c.sorted
+		c.sortedMu.Unlock()
+	}
+	live := make([]string, 0, len(keys))
+	for _, k := range keys {
+		if !c.data[k].expired(now) {
+			live = append(live, k)
+		}
+	}
+	return live
+}
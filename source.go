@@ -0,0 +1,106 @@
+package cache
+
+import (
+	"fmt"
+	"time"
+)
+
+// Source records how an item got into the cache.
+type Source uint8
+
+const (
+	// SourceLoader marks items installed by a load.
+	SourceLoader Source = iota
+	// SourceManual marks items stored by Add, AddWithTTL, a Txn or any
+	// other explicit write. A later load that produces the same key flips
+	// it back to SourceLoader.
+	SourceManual
+)
+
+// String returns the source's name.
+func (s Source) String() string {
+	switch s {
+	case SourceLoader:
+		return "loader"
+	case SourceManual:
+		return "manual"
+	}
+	return fmt.Sprintf("Source(%d)", int(s))
+}
+
+// EntryMeta is an item's value together with what the cache knows about
+// it.
+type EntryMeta[T any] struct {
+	Key    string
+	Value  T
+	Source Source
+	// ExpiresAt is the item's expiry, or the zero time if it never
+	// expires.
+	ExpiresAt time.Time
+	// Version is the item's version; see GetVersioned.
+	Version uint64
+}
+
+// GetWithMeta returns the item for key along with its metadata. Unlike Get
+// it neither counts towards hit/miss statistics nor extends a sliding
+// expiry, so it is safe to use for inspection.
+func (c *Cache[T]) GetWithMeta(key string) (EntryMeta[T], bool) {
+	key = c.normKey(key)
+	now := c.clock.Now()
+	c.clearExpiredData(now)
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	key = c.resolveLocked(key)
+	e, ok := c.data[key]
+	if !ok || e.expired(now) {
+		return EntryMeta[T]{}, false
+	}
+	return c.metaOf(key, e), true
+}
+
+// EntriesWithMeta returns every live item with its metadata, in no
+// particular order.
+func (c *Cache[T]) EntriesWithMeta() []EntryMeta[T] {
+	now := c.clock.Now()
+	c.clearExpiredData(now)
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	entries := make([]EntryMeta[T], 0, len(c.data))
+	for k, e := range c.data {
+		if !e.expired(now) {
+			entries = append(entries, c.metaOf(k, e))
+		}
+	}
+	return entries
+}
+
+// KeysBySource returns the keys of all live items that came from source.
+func (c *Cache[T]) KeysBySource(source Source) []string {
+	now := c.clock.Now()
+	c.clearExpiredData(now)
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	var keys []string
+	for k, e := range c.data {
+		if !e.expired(now) && e.source == source {
+			keys = append(keys, k)
+		}
+	}
+	return keys
+}
+
+// metaOf builds the public metadata for e, stored under key.
+func (c *Cache[T]) metaOf(key string, e *entry[T]) EntryMeta[T] {
+	m := EntryMeta[T]{Key: key, Value: c.output(e.val()), Source: e.source, Version: e.version}
+	if exp := e.expiresAt.Load(); exp != 0 {
+		m.ExpiresAt = time.Unix(0, exp)
+	}
+	return m
+}
+
+// manualEntry builds the entry for an explicit write of value.
+func (c *Cache[T]) manualEntry(value T, expiresAt int64) *entry[T] {
+	e := c.makeEntry(c.input(value), expiresAt)
+	e.source = SourceManual
+	return e
+}
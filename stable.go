@@ -0,0 +1,35 @@
+package cache
+
+import "time"
+
+// WithStableIteration makes Range, Keys, Find, FindKeys, FindOne, FindSeq,
+// FindParallel, ForEachParallel and Snapshot iteration visit keys in
+// ascending order, so batch jobs walking the cache produce identical
+// output across runs. ForEachParallel dispatches in that order, though
+// its workers may still finish out of order. The order comes from the
+// cache behind SortedKeys: after each change to the contents the next
+// iteration pays for a sort, and every iteration and Snapshot for a copy
+// of the keys. By default iteration follows Go's randomized map order.
+func WithStableIteration[T any](enabled bool) Option[T] {
+	return func(c *Cache[T]) {
+		c.stable = enabled
+	}
+}
+
+// eachLocked calls fn for each live entry until fn returns false, in key
+// order under WithStableIteration. The caller must hold c.mu.
+func (c *Cache[T]) eachLocked(now time.Time, fn func(key string, e *entry[T]) bool) {
+	if c.stable {
+		for _, k := range c.sortedKeysLocked(now) {
+			if !fn(k, c.data[k]) {
+				return
+			}
+		}
+		return
+	}
+	for k, e := range c.data {
+		if !e.expired(now) && !fn(k, e) {
+			return
+		}
+	}
+}
@@ -0,0 +1,200 @@
+package cache
+
+import (
+	"sync/atomic"
+	"time"
+)
+
+// Stats is a point-in-time copy of the cache's counters and gauges. It is a
+// plain value, so two snapshots can be compared safely.
+//
+// Counters (Hits, Misses, Loads, LoadErrors, PartialLoads, Throttled,
+// Evictions, Invalid, FrozenRejections, the Shadow counts and the
+// WriteBehind counts) only ever increase, except that ResetStats sets them
+// back to zero. Exporters feeding monotonic counter types such as
+// Prometheus counters should therefore never call ResetStats; use
+// StatsSince to compute per-window deltas instead. Gauges (Items, the load
+// durations, Weight and the compression sizes) describe the current state
+// and are unaffected by ResetStats. See StatsByDimension and
+// PartitionStats for finer-grained statistics.
+type Stats struct {
+	// Name is the cache's WithName name, empty if unnamed, so exported
+	// statistics can be told apart.
+	Name string
+	// Hits and Misses count Get-style lookups.
+	Hits   uint64
+	Misses uint64
+	// Loads and LoadErrors count successful and failed loads.
+	Loads      uint64
+	LoadErrors uint64
+	// PartialLoads counts loads that installed partial data under the
+	// InstallPartial policy of WithPartitionedLoader; see PartitionStats.
+	PartialLoads uint64
+	// Throttled counts Reload calls rejected or deferred by
+	// WithMinReloadInterval.
+	Throttled uint64
+	// Evictions counts items evicted by WithMaxEntries or WithMaxWeight.
+	Evictions uint64
+	// Invalid counts values rejected by WithTransform,
+	// WithValueValidator or WithRejectNil.
+	Invalid uint64
+	// ShadowRuns counts WithShadowLoader comparisons, ShadowErrors those
+	// where the shadow loader failed, and ShadowMismatches the disagreeing
+	// keys found across all of them.
+	ShadowRuns       uint64
+	ShadowErrors     uint64
+	ShadowMismatches uint64
+	// FrozenRejections counts loads and writes ignored while frozen.
+	FrozenRejections uint64
+	// WriteBehindErrors counts failed WithWriteBehind sink calls, and
+	// WriteBehindDropped the ops dropped by DropWhenFull.
+	WriteBehindErrors  uint64
+	WriteBehindDropped uint64
+
+	// Items is the current number of stored items.
+	Items int
+	// LastLoadDuration is how long the most recent load took, successful
+	// or not, and AvgLoadDuration the rolling average of recent loads.
+	LastLoadDuration time.Duration
+	AvgLoadDuration  time.Duration
+	// Weight is the total weight of the items per WithWeigher, each item
+	// weighing 1 without one. It is 0 unless a weigher or limit is set.
+	Weight int64
+	// CompressedItems is the number of items stored compressed, and
+	// LogicalBytes and CompressedBytes are their serialized sizes before
+	// and after compression.
+	CompressedItems int
+	LogicalBytes    int64
+	CompressedBytes int64
+	// Since is when counting started: construction or the last ResetStats.
+	Since time.Time
+
+	epoch uint64 // number of ResetStats calls, to detect resets
+}
+
+// counters holds the live, atomically updated statistics.
+type counters struct {
+	hits         atomic.Uint64
+	misses       atomic.Uint64
+	loads        atomic.Uint64
+	loadErrors   atomic.Uint64
+	partialLoads atomic.Uint64
+	throttled    atomic.Uint64
+	evictions    atomic.Uint64
+	invalid      atomic.Uint64
+
+	shadowRuns       atomic.Uint64
+	shadowErrors     atomic.Uint64
+	shadowMismatches atomic.Uint64
+	frozenRejections atomic.Uint64
+
+	writeBehindErrors  atomic.Uint64
+	writeBehindDropped atomic.Uint64
+	epoch              uint64 // guarded by Cache.mu
+}
+
+// Stats returns a copy of the current statistics.
+func (c *Cache[T]) Stats() Stats {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	s := Stats{
+		Name:               c.name,
+		Hits:               c.stats.hits.Load(),
+		Misses:             c.stats.misses.Load(),
+		Loads:              c.stats.loads.Load(),
+		LoadErrors:         c.stats.loadErrors.Load(),
+		PartialLoads:       c.stats.partialLoads.Load(),
+		Throttled:          c.stats.throttled.Load(),
+		Evictions:          c.stats.evictions.Load(),
+		Invalid:            c.stats.invalid.Load(),
+		ShadowRuns:         c.stats.shadowRuns.Load(),
+		ShadowErrors:       c.stats.shadowErrors.Load(),
+		ShadowMismatches:   c.stats.shadowMismatches.Load(),
+		FrozenRejections:   c.stats.frozenRejections.Load(),
+		WriteBehindErrors:  c.stats.writeBehindErrors.Load(),
+		WriteBehindDropped: c.stats.writeBehindDropped.Load(),
+		Items:              len(c.data),
+		LastLoadDuration:   c.reloadTimes.last(),
+		AvgLoadDuration:    c.reloadTimes.mean(),
+		CompressedItems:    int(c.compStats.items.Load()),
+		LogicalBytes:       c.compStats.logical.Load(),
+		CompressedBytes:    c.compStats.stored.Load(),
+		Since:              c.statsSince,
+		epoch:              c.stats.epoch,
+	}
+	if c.lru != nil {
+		s.Weight = c.lru.weight
+	}
+	return s
+}
+
+// ResetStats zeroes all counters and restarts the counting window. It is
+// meant for tests and window-based reporting; see Stats for the caveats.
+func (c *Cache[T]) ResetStats() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.stats.hits.Store(0)
+	c.stats.misses.Store(0)
+	c.stats.loads.Store(0)
+	c.stats.loadErrors.Store(0)
+	c.stats.partialLoads.Store(0)
+	c.stats.throttled.Store(0)
+	c.stats.evictions.Store(0)
+	c.stats.invalid.Store(0)
+	c.stats.shadowRuns.Store(0)
+	c.stats.shadowErrors.Store(0)
+	c.stats.shadowMismatches.Store(0)
+	c.stats.frozenRejections.Store(0)
+	c.stats.writeBehindErrors.Store(0)
+	c.stats.writeBehindDropped.Store(0)
+	if c.dims != nil {
+		c.dims.reset()
+	}
+	if c.partitions != nil {
+		c.partitions.resetCounts()
+	}
+	c.stats.epoch++
+	c.statsSince = c.clock.Now()
+}
+
+// StatsSince returns the counter deltas accumulated since prev was taken,
+// with gauges reflecting the current state. If the counters were reset in
+// between, the deltas count from the reset. Since is the later of
+// prev.Since and the last reset, so a zero prev yields the counts and
+// start time of Stats.
+func (c *Cache[T]) StatsSince(prev Stats) Stats {
+	cur := c.Stats()
+	if cur.epoch != prev.epoch {
+		return cur
+	}
+	cur.Hits -= prev.Hits
+	cur.Misses -= prev.Misses
+	cur.Loads -= prev.Loads
+	cur.LoadErrors -= prev.LoadErrors
+	cur.PartialLoads -= prev.PartialLoads
+	cur.Throttled -= prev.Throttled
+	cur.Evictions -= prev.Evictions
+	cur.Invalid -= prev.Invalid
+	cur.ShadowRuns -= prev.ShadowRuns
+	cur.ShadowErrors -= prev.ShadowErrors
+	cur.ShadowMismatches -= prev.ShadowMismatches
+	cur.FrozenRejections -= prev.FrozenRejections
+	cur.WriteBehindErrors -= prev.WriteBehindErrors
+	cur.WriteBehindDropped -= prev.WriteBehindDropped
+	if prev.Since.After(cur.Since) {
+		cur.Since = prev.Since
+	}
+	return cur
+}
+
+// recordLookup counts a Get-style lookup of key as a hit or a miss.
+func (c *Cache[T]) recordLookup(key string, hit bool) {
+	if hit {
+		c.stats.hits.Add(1)
+	} else {
+		c.stats.misses.Add(1)
+	}
+	if c.dims != nil {
+		c.dims.record(key, hit)
+	}
+}
@@ -0,0 +1,83 @@
+package cache_test
+
+import (
+	"encoding/json"
+	"errors"
+	"expvar"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/TheOrchestraX/cache"
+)
+
+func TestStatsName(t *testing.T) {
+	c, _ := newTestCache(t, map[string]int{"a": 1}, cache.WithName[int]("users"))
+	mustGet(t, c, "a")
+	mustMiss(t, c, "b")
+	s := c.Stats()
+	if s.Name != "users" || s.Hits != 1 || s.Misses != 1 {
+		t.Fatalf("Stats = %+v, want Name users, 1 hit, 1 miss", s)
+	}
+	if d := c.StatsSince(s); d.Name != "users" {
+		t.Fatalf("StatsSince Name = %q, want users", d.Name)
+	}
+	if got := c.String(); !strings.HasPrefix(got, "users: 1 items") {
+		t.Fatalf("String = %q, want users prefix", got)
+	}
+}
+
+func TestPublishExpvar(t *testing.T) {
+	c, _ := newTestCache(t, map[string]int{"a": 1}, cache.WithName[int]("expvar-test"))
+	if err := c.PublishExpvar(); err != nil {
+		t.Fatalf("PublishExpvar: %v", err)
+	}
+	if err := c.PublishExpvar(); err == nil {
+		t.Fatal("second PublishExpvar succeeded, want error")
+	}
+	mustGet(t, c, "a")
+	v := expvar.Get("cache.expvar-test")
+	if v == nil {
+		t.Fatal("cache.expvar-test not published")
+	}
+	var s cache.Stats
+	if err := json.Unmarshal([]byte(v.String()), &s); err != nil {
+		t.Fatalf("decoding %s: %v", v, err)
+	}
+	if s.Name != "expvar-test" || s.Hits != 1 {
+		t.Fatalf("published Stats = %+v, want Name expvar-test and 1 hit", s)
+	}
+
+	unnamed, _ := newTestCache(t, map[string]int{})
+	if err := unnamed.PublishExpvar(); !errors.Is(err, cache.ErrUnnamed) {
+		t.Fatalf("unnamed PublishExpvar = %v, want ErrUnnamed", err)
+	}
+}
+
+func TestStatsSinceWindowStart(t *testing.T) {
+	c, clk := newTestCache(t, map[string]int{"a": 1})
+	start := clk.Now()
+
+	// A zero prev, as an exporter has before its first scrape, counts from
+	// construction.
+	mustGet(t, c, "a")
+	if d := c.StatsSince(cache.Stats{}); d.Hits != 1 || !d.Since.Equal(start) {
+		t.Fatalf("StatsSince(zero) = %d hits since %v, want 1 since %v", d.Hits, d.Since, start)
+	}
+
+	prev := c.Stats()
+	clk.Advance(time.Minute)
+	c.ResetStats()
+	reset := clk.Now()
+	mustGet(t, c, "a")
+	mustGet(t, c, "a")
+	if d := c.StatsSince(prev); d.Hits != 2 || !d.Since.Equal(reset) {
+		t.Fatalf("StatsSince across a reset = %d hits since %v, want 2 since %v", d.Hits, d.Since, reset)
+	}
+
+	prev = c.Stats()
+	mustGet(t, c, "a")
+	if d := c.StatsSince(prev); d.Hits != 1 || !d.Since.Equal(reset) {
+		t.Fatalf("StatsSince = %d hits since %v, want 1 since %v", d.Hits, d.Since, reset)
+	}
+}
@@ -0,0 +1,76 @@
+package cache
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// StreamLoader produces a cache's contents one entry at a time by calling
+// emit, instead of returning a complete map. If emit returns an error the
+// loader should stop and return it; this is how a cancelled load, e.g. by
+// StopAutoReload, is cut short. emit must be called from the loader's
+// own goroutine and not after the loader returns.
+type StreamLoader[T any] func(emit func(key string, value T) error) error
+
+// NewStreamingCache constructs a Cache filled by a StreamLoader. Emitted
+// entries are accumulated directly into the replacement map, so the loader
+// never has to hold its own copy of the dataset. The swap is still atomic:
+// readers see the old contents until the loader returns successfully, and
+// if it fails part-way the partial map is discarded.
+func NewStreamingCache[T any](load StreamLoader[T], interval time.Duration, opts ...Option[T]) *Cache[T] {
+	c := NewCache[T](nil, interval, opts...)
+	c.stream = load
+	return c
+}
+
+// fetchStream runs the stream loader, building entries as they arrive.
+// When a key is emitted more than once, the last value wins.
+func (c *Cache[T]) fetchStream(ctx context.Context) (map[string]*entry[T], error) {
+	exp := c.loadExpiry()
+	data := make(map[string]*entry[T])
+	var drops invalidDrops
+	err := c.callStream(ctx, func(key string, value T) error {
+		value, keep, err := c.checkLoaded(key, value, &drops)
+		if keep {
+			data[key] = c.makeEntry(value, exp)
+		}
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	c.logDrops(drops)
+	return data, nil
+}
+
+// callStream feeds the loader's entries to emit, adapting a map loader when
+// no stream loader is set. Once ctx is cancelled, emit fails with its
+// error so the loader stops early. A panicking loader is reported as
+// ErrLoaderPanic.
+func (c *Cache[T]) callStream(ctx context.Context, emit func(key string, value T) error) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("%w: %v", ErrLoaderPanic, r)
+		}
+	}()
+	load, stream := c.loaders()
+	if stream != nil {
+		return stream(func(key string, value T) error {
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+			return emit(c.normKey(key), value)
+		})
+	}
+	result, err := c.checkResult(load(ctx))
+	if err != nil {
+		return err
+	}
+	for k, v := range c.normalizeItems(result) {
+		if err := emit(k, v); err != nil {
+			return err
+		}
+	}
+	return nil
+}
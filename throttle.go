@@ -0,0 +1,61 @@
+package cache
+
+import "time"
+
+// WithMinReloadInterval limits how often Reload actually runs the loader:
+// a call arriving less than d after the previous load finished is
+// throttled. By default it fails with ErrReloadThrottled; with coalesce
+// set it instead waits for the next allowed slot, and every call waiting
+// for the same slot shares a single load and its result. Load, automatic
+// reloads and TriggerReload are not throttled. Throttled calls are counted
+// in Stats.Throttled.
+func WithMinReloadInterval[T any](d time.Duration, coalesce bool) Option[T] {
+	return func(c *Cache[T]) {
+		c.minReload = d
+		c.coalesce = coalesce
+	}
+}
+
+// pendingReload is a coalesced reload that callers wait on together.
+type pendingReload struct {
+	done chan struct{}
+	err  error
+}
+
+// throttledReload implements Reload under WithMinReloadInterval.
+func (c *Cache[T]) throttledReload() error {
+	c.throttleMu.Lock()
+	if p := c.nextReload; p != nil {
+		c.throttleMu.Unlock()
+		c.stats.throttled.Add(1)
+		<-p.done
+		return p.err
+	}
+	c.mu.RLock()
+	last := c.lastAttempt
+	c.mu.RUnlock()
+	wait := last.Add(c.minReload).Sub(c.clock.Now())
+	if last.IsZero() || wait <= 0 {
+		// Loading under throttleMu makes concurrent callers queue
+		// behind this load and then see it as the previous one.
+		defer c.throttleMu.Unlock()
+		return c.Load()
+	}
+	c.stats.throttled.Add(1)
+	if !c.coalesce {
+		c.throttleMu.Unlock()
+		return ErrReloadThrottled
+	}
+	p := &pendingReload{done: make(chan struct{})}
+	c.nextReload = p
+	c.throttleMu.Unlock()
+
+	t := c.clock.NewTimer(wait)
+	<-t.C()
+	p.err = c.Load()
+	c.throttleMu.Lock()
+	c.nextReload = nil
+	c.throttleMu.Unlock()
+	close(p.done)
+	return p.err
+}
@@ -0,0 +1,98 @@
+package cache
+
+import (
+	"cmp"
+	"slices"
+	"sync/atomic"
+)
+
+// cacheIDs numbers caches in construction order, the order in which
+// SnapshotTogether locks them.
+var cacheIDs atomic.Uint64
+
+// AnySnapshot is the non-generic read surface of a Snapshot, for code
+// handling snapshots of caches with different value types together, like
+// Inspector for caches. *Snapshot[T] implements it for every T.
+type AnySnapshot interface {
+	GetAny(key string) (any, bool)
+	RangeAny(fn func(key string, value any) bool)
+	Len() int
+	Generation() uint64
+}
+
+var _ AnySnapshot = (*Snapshot[int])(nil)
+
+// GetAny is Get with the value boxed in an interface.
+func (s *Snapshot[T]) GetAny(key string) (any, bool) {
+	v, ok := s.Get(key)
+	if !ok {
+		return nil, false
+	}
+	return v, true
+}
+
+// RangeAny is Range with the values boxed in an interface.
+func (s *Snapshot[T]) RangeAny(fn func(key string, value any) bool) {
+	s.Range(func(k string, v T) bool { return fn(k, v) })
+}
+
+// Snapshotter is a cache that SnapshotTogether can capture. *Cache[T]
+// implements it for every T; it cannot be implemented outside the package.
+type Snapshotter interface {
+	snapshotID() uint64
+	clearExpired()
+	rlock()
+	runlock()
+	anySnapshotLocked() AnySnapshot
+}
+
+var _ Snapshotter = (*Cache[int])(nil)
+
+func (c *Cache[T]) snapshotID() uint64             { return c.id }
+func (c *Cache[T]) clearExpired()                  { c.clearExpiredData(c.clock.Now()) }
+func (c *Cache[T]) rlock()                         { c.mu.RLock() }
+func (c *Cache[T]) runlock()                       { c.mu.RUnlock() }
+func (c *Cache[T]) anySnapshotLocked() AnySnapshot { return c.snapshotLocked() }
+
+// SnapshotTogether captures a Snapshot of each cache as of one instant,
+// for checks of invariants spanning caches, such as orders referring to
+// customers, that must not see one cache before a reload and another after
+// it. The result holds the snapshots in argument order.
+//
+// The guarantee is exact: SnapshotTogether read-locks every cache, in
+// construction order so that concurrent calls cannot deadlock, before
+// capturing any snapshot, and releases them all afterwards. No load or
+// write to any of the caches, applied under its write lock, can therefore
+// fall between two of the captures. It does not order them against loads
+// still running: a load in flight is captured by none of the snapshots
+// until it installs its data. The locks are held only for the captures,
+// which copy nothing unless WithStableIteration is set, so writers are
+// delayed briefly. A cache passed more than once is captured once, its
+// snapshot repeated.
+func SnapshotTogether(caches ...Snapshotter) []AnySnapshot {
+	order := slices.Clone(caches)
+	slices.SortFunc(order, func(a, b Snapshotter) int {
+		return cmp.Compare(a.snapshotID(), b.snapshotID())
+	})
+	order = slices.CompactFunc(order, func(a, b Snapshotter) bool {
+		return a.snapshotID() == b.snapshotID()
+	})
+	for _, c := range order {
+		c.clearExpired()
+	}
+	for _, c := range order {
+		c.rlock()
+	}
+	byID := make(map[uint64]AnySnapshot, len(order))
+	for _, c := range order {
+		byID[c.snapshotID()] = c.anySnapshotLocked()
+	}
+	for i := len(order) - 1; i >= 0; i-- {
+		order[i].runlock()
+	}
+	snaps := make([]AnySnapshot, len(caches))
+	for i, c := range caches {
+		snaps[i] = byID[c.snapshotID()]
+	}
+	return snaps
+}
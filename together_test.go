@@ -0,0 +1,95 @@
+package cache_test
+
+import (
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/TheOrchestraX/cache"
+)
+
+func TestSnapshotTogetherConsistentUnderReloads(t *testing.T) {
+	// Each round reloads orders to version v, then customers to v, so at
+	// any instant orders is at customers' version or one ahead. Snapshots
+	// not taken together could see customers ahead of orders.
+	var orderVer, customerVer atomic.Int64
+	silent := cache.WithLogLevel[int](cache.LogSilent)
+	orders := cache.NewCache(func() (map[string]int, error) {
+		return map[string]int{"version": int(orderVer.Load())}, nil
+	}, 0, silent)
+	customers := cache.NewCache(func() (map[string]string, error) {
+		return map[string]string{"version": strconv.FormatInt(customerVer.Load(), 10)}, nil
+	}, 0, cache.WithLogLevel[string](cache.LogSilent))
+	orders.Load()
+	customers.Load()
+
+	const rounds = 5000
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for v := int64(1); v <= rounds; v++ {
+			orderVer.Store(v)
+			orders.Load()
+			customerVer.Store(v)
+			customers.Load()
+		}
+	}()
+	// The checkers run until the writer is done. The second passes the
+	// caches the other way round, which must not deadlock against the
+	// first.
+	check := func(reversed bool) {
+		defer wg.Done()
+		for customerVer.Load() < rounds {
+			var o, c any
+			if reversed {
+				snaps := cache.SnapshotTogether(customers, orders)
+				c, _ = snaps[0].GetAny("version")
+				o, _ = snaps[1].GetAny("version")
+			} else {
+				snaps := cache.SnapshotTogether(orders, customers)
+				o, _ = snaps[0].GetAny("version")
+				c, _ = snaps[1].GetAny("version")
+			}
+			ov := int64(o.(int))
+			cv, err := strconv.ParseInt(c.(string), 10, 64)
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			if ov != cv && ov != cv+1 {
+				t.Errorf("snapshots saw orders at version %d and customers at %d", ov, cv)
+				return
+			}
+		}
+	}
+	wg.Add(2)
+	go check(false)
+	go check(true)
+	wg.Wait()
+}
+
+func TestSnapshotTogetherArgumentOrder(t *testing.T) {
+	a, _ := newTestCache(t, map[string]int{"k": 1})
+	b, _ := newTestCache(t, map[string]string{"k": "b"})
+	snaps := cache.SnapshotTogether(b, a, b)
+	if len(snaps) != 3 {
+		t.Fatalf("got %d snapshots, want 3", len(snaps))
+	}
+	for i, want := range []any{"b", 1, "b"} {
+		if v, ok := snaps[i].GetAny("k"); !ok || v != want {
+			t.Fatalf("snapshot %d: GetAny(k) = %v, %v; want %v", i, v, ok, want)
+		}
+	}
+	if snaps[0] != snaps[2] {
+		t.Fatal("a cache passed twice was captured twice")
+	}
+	a.Add("k", 2)
+	if v, _ := snaps[1].GetAny("k"); v != 1 {
+		t.Fatalf("snapshot changed after a write: GetAny(k) = %v, want 1", v)
+	}
+	if snaps[1].Generation() == a.Generation() {
+		t.Fatal("snapshot Generation advanced with the cache")
+	}
+}
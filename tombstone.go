@@ -0,0 +1,71 @@
+package cache
+
+import "time"
+
+// DeleteWithTombstone removes key and, for ttl, stops reloads from putting
+// it back: a reload whose data still contains key has it stripped before
+// the swap, as do Prime, Import, UnmarshalJSON and bootstrap data. This
+// suits eventually consistent sources that keep returning a deleted item
+// for a while. An explicit write of key lifts the tombstone.
+func (c *Cache[T]) DeleteWithTombstone(key string, ttl time.Duration) {
+	key = c.normKey(key)
+	if c.rejectFrozen("delete") {
+		return
+	}
+	now := c.clock.Now()
+	c.mu.Lock()
+	key = c.resolveLocked(key)
+	_, ok := c.deleteLocked(key)
+	var muts []mutation[T]
+	if ok {
+		muts = []mutation[T]{{key: key, deleted: true}}
+		c.writeBehindStageLocked(muts)
+	}
+	if ttl > 0 {
+		if c.tombstones == nil {
+			c.tombstones = make(map[string]int64)
+		}
+		c.tombstones[key] = now.Add(ttl).UnixNano()
+	}
+	h := c.hooks
+	c.mu.Unlock()
+	if ok {
+		c.notify(h, muts)
+	}
+}
+
+// Tombstones returns the keys currently shielded from reloads by
+// DeleteWithTombstone, with the time each tombstone lapses.
+func (c *Cache[T]) Tombstones() map[string]time.Time {
+	now := c.clock.Now().UnixNano()
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	result := make(map[string]time.Time, len(c.tombstones))
+	for k, exp := range c.tombstones {
+		if now < exp {
+			result[k] = time.Unix(0, exp)
+		}
+	}
+	return result
+}
+
+// tombstonedLocked reports whether key has a live tombstone at now. The
+// caller must hold c.mu.
+func (c *Cache[T]) tombstonedLocked(key string, now int64) bool {
+	exp, ok := c.tombstones[key]
+	return ok && now < exp
+}
+
+// stripTombstonedLocked removes tombstoned keys from data, about to be
+// installed by a reload, and forgets lapsed tombstones. The caller must
+// hold c.mu for writing.
+func (c *Cache[T]) stripTombstonedLocked(data map[string]*entry[T]) {
+	now := c.clock.Now().UnixNano()
+	for k, exp := range c.tombstones {
+		if now >= exp {
+			delete(c.tombstones, k)
+			continue
+		}
+		delete(data, k)
+	}
+}
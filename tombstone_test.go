@@ -0,0 +1,65 @@
+package cache_test
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/TheOrchestraX/cache"
+)
+
+func TestTombstoneBlocksReloadUntilLapsed(t *testing.T) {
+	c, clk := newTestCache(t, map[string]int{"a": 1, "b": 2})
+	c.DeleteWithTombstone("a", time.Minute)
+	if err := c.Load(); err != nil {
+		t.Fatal(err)
+	}
+	mustMiss(t, c, "a")
+	mustGet(t, c, "b")
+	clk.Advance(time.Minute)
+	if err := c.Load(); err != nil {
+		t.Fatal(err)
+	}
+	mustGet(t, c, "a")
+}
+
+func TestTombstoneWriteLiftsIt(t *testing.T) {
+	c, _ := newTestCache(t, map[string]int{"a": 1})
+	c.DeleteWithTombstone("a", time.Minute)
+	c.Add("a", 5)
+	if len(c.Tombstones()) != 0 {
+		t.Fatalf("Tombstones = %v after a write, want none", c.Tombstones())
+	}
+	if err := c.Load(); err != nil {
+		t.Fatal(err)
+	}
+	if v := mustGet(t, c, "a"); v != 1 {
+		t.Fatalf("Get(a) = %d, want the reloaded 1", v)
+	}
+}
+
+func TestTombstoneStoresPaths(t *testing.T) {
+	items := map[string]int{"a": 1, "b": 2}
+	blob, err := json.Marshal(items)
+	if err != nil {
+		t.Fatal(err)
+	}
+	dump := exported(t, items)
+	paths := map[string]func(c *cache.Cache[int]) error{
+		"Prime":         func(c *cache.Cache[int]) error { c.Prime(items); return nil },
+		"Import":        func(c *cache.Cache[int]) error { _, err := c.Import(strings.NewReader(dump)); return err },
+		"UnmarshalJSON": func(c *cache.Cache[int]) error { return c.UnmarshalJSON(blob) },
+	}
+	for name, store := range paths {
+		t.Run(name, func(t *testing.T) {
+			c, _ := newTestCache(t, items)
+			c.DeleteWithTombstone("a", time.Minute)
+			if err := store(c); err != nil {
+				t.Fatalf("%s: %v", name, err)
+			}
+			mustMiss(t, c, "a")
+			mustGet(t, c, "b")
+		})
+	}
+}
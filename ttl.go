@@ -0,0 +1,148 @@
+package cache
+
+import "time"
+
+// NoExpiry is returned by TTL for items that never expire.
+const NoExpiry time.Duration = -1
+
+// AddWithTTL inserts or updates a single item that expires after ttl,
+// overriding any default TTL. A ttl <= 0 means the item never expires.
+func (c *Cache[T]) AddWithTTL(key string, value T, ttl time.Duration) {
+	key = c.normKey(key)
+	value, ok := c.validWrite(key, value)
+	if !ok || c.rejectFrozen("add") {
+		return
+	}
+	now := c.clock.Now()
+	e := c.manualEntry(value, expiryFor(now, ttl))
+	c.mu.Lock()
+	muts := append([]mutation[T]{c.setNotifiedLocked(key, value, e, now)}, c.evictLocked()...)
+	c.writeBehindStageLocked(muts)
+	h := c.hooks
+	c.mu.Unlock()
+	c.notify(h, muts)
+}
+
+// Touch resets the expiration of an existing item to ttl from now, without
+// replacing its value. A ttl <= 0 makes the item permanent. It returns false
+// if the key is absent or already expired.
+func (c *Cache[T]) Touch(key string, ttl time.Duration) bool {
+	_, ok := c.touch(c.normKey(key), ttl)
+	return ok
+}
+
+// GetAndTouch returns the item for a key and resets its expiration to ttl
+// from now, as a single operation.
+func (c *Cache[T]) GetAndTouch(key string, ttl time.Duration) (T, bool) {
+	key = c.normKey(key)
+	e, ok := c.touch(key, ttl)
+	c.recordLookup(key, ok)
+	if ok && c.lru != nil {
+		c.lru.used(e)
+	}
+	if !ok {
+		var zero T
+		return zero, false
+	}
+	return c.output(e.val()), true
+}
+
+// touch resets the expiration of a live entry and returns it. key must
+// already be normalized.
+func (c *Cache[T]) touch(key string, ttl time.Duration) (*entry[T], bool) {
+	now := c.clock.Now()
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	key = c.resolveLocked(key)
+	e, ok := c.data[key]
+	if !ok || e.expired(now) {
+		return nil, false
+	}
+	if c.isPinnedLocked(key) {
+		return e, true
+	}
+	e.expiresAt.Store(expiryFor(now, ttl))
+	return e, true
+}
+
+// Expire sets or replaces the TTL of an existing item, counting from now.
+// A d <= 0 removes the TTL so the item never expires. It returns false if
+// the key is absent or already expired.
+func (c *Cache[T]) Expire(key string, d time.Duration) bool {
+	return c.Touch(key, d)
+}
+
+// TTL returns the remaining lifetime of an item, or NoExpiry if it never
+// expires. ok is false if the key is absent or already expired.
+func (c *Cache[T]) TTL(key string) (time.Duration, bool) {
+	key = c.normKey(key)
+	now := c.clock.Now()
+	c.clearExpiredData(now)
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	e, ok := c.data[c.resolveLocked(key)]
+	if !ok || e.expired(now) {
+		return 0, false
+	}
+	exp := e.expiresAt.Load()
+	if exp == 0 {
+		return NoExpiry, true
+	}
+	return time.Duration(exp - now.UnixNano()), true
+}
+
+// SetDefaultTTL changes the default TTL at runtime. Only items inserted
+// afterwards are affected; existing expirations are left untouched.
+// A d <= 0 disables the default TTL.
+func (c *Cache[T]) SetDefaultTTL(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.defaultTTL = d
+}
+
+// DeleteExpired removes every expired item and returns how many were
+// removed. Reads already ignore expired items; this reclaims their memory.
+func (c *Cache[T]) DeleteExpired() int {
+	now := c.clock.Now()
+	c.mu.Lock()
+	n := 0
+	for k, e := range c.data {
+		if e.expired(now) {
+			c.deleteLocked(k)
+			n++
+		}
+	}
+	for k, exp := range c.negative {
+		if now.UnixNano() >= exp {
+			delete(c.negative, k)
+		}
+	}
+	for k, exp := range c.tombstones {
+		if now.UnixNano() >= exp {
+			delete(c.tombstones, k)
+		}
+	}
+	c.mu.Unlock()
+	if n > 0 {
+		c.sizeChanged()
+	}
+	return n
+}
+
+// addTTL returns the TTL for items inserted by Add. The caller must hold
+// c.mu.
+func (c *Cache[T]) addTTL() time.Duration {
+	if c.defaultTTL > 0 {
+		return c.defaultTTL
+	}
+	return c.slidingTTL
+}
+
+// loadTTL returns the TTL for items installed by Load. The caller must hold
+// c.mu.
+func (c *Cache[T]) loadTTL() time.Duration {
+	if c.defaultTTLOnLoad && c.defaultTTL > 0 {
+		return c.defaultTTL
+	}
+	return c.slidingTTL
+}
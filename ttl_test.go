@@ -0,0 +1,163 @@
+package cache_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/TheOrchestraX/cache"
+)
+
+func TestExpireShorterAndLonger(t *testing.T) {
+	c, clk := newTestCache(t, map[string]int{})
+	c.AddWithTTL("short", 1, time.Hour)
+	c.AddWithTTL("long", 2, time.Minute)
+
+	if !c.Expire("short", time.Minute) {
+		t.Fatal("Expire(short) = false")
+	}
+	if !c.Expire("long", time.Hour) {
+		t.Fatal("Expire(long) = false")
+	}
+	if d, ok := c.TTL("short"); !ok || d != time.Minute {
+		t.Fatalf("TTL(short) = %v, %v; want 1m", d, ok)
+	}
+
+	clk.Advance(2 * time.Minute)
+	mustMiss(t, c, "short")
+	if got := mustGet(t, c, "long"); got != 2 {
+		t.Fatalf("Get(long) = %d", got)
+	}
+	if d, ok := c.TTL("long"); !ok || d != 58*time.Minute {
+		t.Fatalf("TTL(long) = %v, %v; want 58m", d, ok)
+	}
+}
+
+func TestExpireRemovesTTL(t *testing.T) {
+	c, clk := newTestCache(t, map[string]int{})
+	c.AddWithTTL("k", 1, time.Minute)
+	if !c.Expire("k", 0) {
+		t.Fatal("Expire(k, 0) = false")
+	}
+	if d, ok := c.TTL("k"); !ok || d != cache.NoExpiry {
+		t.Fatalf("TTL(k) = %v, %v; want NoExpiry", d, ok)
+	}
+	clk.Advance(24 * time.Hour)
+	mustGet(t, c, "k")
+}
+
+func TestTTLAbsentOrExpired(t *testing.T) {
+	c, clk := newTestCache(t, map[string]int{})
+	if _, ok := c.TTL("missing"); ok {
+		t.Fatal("TTL(missing) ok")
+	}
+	if c.Expire("missing", time.Minute) {
+		t.Fatal("Expire(missing) = true")
+	}
+	c.AddWithTTL("k", 1, time.Second)
+	clk.Advance(time.Second)
+	if _, ok := c.TTL("k"); ok {
+		t.Fatal("TTL of expired key ok")
+	}
+	if c.Expire("k", time.Minute) {
+		t.Fatal("Expire revived an expired key")
+	}
+}
+
+func TestTouchPinnedThroughAlias(t *testing.T) {
+	c, _ := newTestCache(t, map[string]string{"p": "x"},
+		cache.WithAliases(func(v string) []string { return []string{v} }))
+	c.Pin("p")
+	if !c.Touch("x", time.Second) {
+		t.Fatal("Touch through alias = false")
+	}
+	if d, ok := c.TTL("p"); !ok || d != cache.NoExpiry {
+		t.Fatalf("TTL(p) = %v, %v; want NoExpiry for a pinned key", d, ok)
+	}
+}
+
+func TestGetAndTouchNormalizesStatsKey(t *testing.T) {
+	c, _ := newTestCache(t, map[string]int{"a": 1},
+		cache.WithKeyNormalizer[int](strings.ToLower),
+		cache.WithStatsDimension[int](func(key string) string { return key }))
+	if _, ok := c.GetAndTouch("A", time.Minute); !ok {
+		t.Fatal("GetAndTouch(A) missing")
+	}
+	dims := c.StatsByDimension()
+	if s := dims["a"]; s.Hits != 1 {
+		t.Fatalf("StatsByDimension = %+v, want 1 hit under a", dims)
+	}
+	if _, ok := dims["A"]; ok {
+		t.Fatal("hit recorded under the raw key A")
+	}
+}
+
+func TestTouchPushesExpiryOut(t *testing.T) {
+	c, clk := newTestCache(t, map[string]int{})
+	c.AddWithTTL("session", 1, time.Minute)
+	clk.Advance(50 * time.Second)
+	if !c.Touch("session", time.Minute) {
+		t.Fatal("Touch(session) = false")
+	}
+	clk.Advance(50 * time.Second) // past the original expiry
+	mustGet(t, c, "session")
+	if v, ok := c.GetAndTouch("session", time.Minute); !ok || v != 1 {
+		t.Fatalf("GetAndTouch = %d, %v; want 1, true", v, ok)
+	}
+	clk.Advance(50 * time.Second)
+	mustGet(t, c, "session")
+	clk.Advance(10 * time.Second)
+	mustMiss(t, c, "session")
+
+	if c.Touch("session", time.Minute) {
+		t.Fatal("Touch of an expired key = true")
+	}
+	if c.Touch("missing", time.Minute) {
+		t.Fatal("Touch of an absent key = true")
+	}
+	if _, ok := c.GetAndTouch("missing", time.Minute); ok {
+		t.Fatal("GetAndTouch of an absent key ok")
+	}
+}
+
+func TestTouchZeroMakesPermanent(t *testing.T) {
+	c, clk := newTestCache(t, map[string]int{})
+	c.AddWithTTL("k", 1, time.Second)
+	if !c.Touch("k", 0) {
+		t.Fatal("Touch(k, 0) = false")
+	}
+	clk.Advance(time.Hour)
+	mustGet(t, c, "k")
+}
+
+func TestSlidingTTLKeepsUsedItems(t *testing.T) {
+	c, clk := newTestCache(t, map[string]int{"loaded": 1}, cache.WithSlidingTTL[int](10*time.Second))
+	c.Add("added", 2)
+	c.AddWithTTL("permanent", 3, 0)
+	for range 3 {
+		clk.Advance(6 * time.Second)
+		mustGet(t, c, "loaded")
+		c.GetAll() // scans do not extend expirations
+	}
+	mustMiss(t, c, "added") // idle for 18s
+	mustGet(t, c, "permanent")
+	if d, ok := c.TTL("loaded"); !ok || d != 10*time.Second {
+		t.Fatalf("TTL(loaded) = %v, %v; want 10s", d, ok)
+	}
+	clk.Advance(10 * time.Second)
+	mustMiss(t, c, "loaded")
+	mustGet(t, c, "permanent")
+}
+
+func TestSlidingTTLAppliesToReloads(t *testing.T) {
+	c, clk := newTestCache(t, map[string]int{"a": 1}, cache.WithSlidingTTL[int](10*time.Second))
+	clk.Advance(9 * time.Second)
+	if err := c.Load(); err != nil {
+		t.Fatal(err)
+	}
+	if d, ok := c.TTL("a"); !ok || d != 10*time.Second {
+		t.Fatalf("TTL(a) after reload = %v, %v; want a fresh 10s", d, ok)
+	}
+	clk.Advance(10 * time.Second)
+	mustMiss(t, c, "a")
+}
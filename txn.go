@@ -0,0 +1,86 @@
+package cache
+
+// Txn stages a group of mutations that are applied atomically when the
+// function passed to Cache.Txn returns. Readers observe either all of the
+// staged changes or none of them.
+type Txn[T any] struct {
+	c      *Cache[T]
+	staged []mutation[T]
+}
+
+// Txn runs fn and then applies every mutation it staged under a single
+// write-lock acquisition. If fn panics, the staged changes are discarded and
+// the panic propagates. Staged writes are validated as Add validates them;
+// if any is rejected, nothing is applied and the error, wrapping
+// ErrInvalidValue, is returned. While the cache is frozen nothing is
+// applied and Txn returns ErrFrozen. Hooks fire once per staged mutation
+// after commit.
+func (c *Cache[T]) Txn(fn func(tx *Txn[T])) error {
+	tx := &Txn[T]{c: c}
+	fn(tx)
+	if len(tx.staged) == 0 {
+		return nil
+	}
+	if c.rejectFrozen("transaction") {
+		return ErrFrozen
+	}
+	// Validation runs user code, so it happens before taking the lock.
+	staged := tx.staged
+	for i, m := range staged {
+		if m.deleted {
+			continue
+		}
+		value, err := c.validate(m.key, m.value)
+		if err != nil {
+			c.logf(LogErrorsOnly, "%s rejected transaction: %v", c.label(), err)
+			return err
+		}
+		staged[i].value = value
+	}
+	c.mu.Lock()
+	ttl := c.addTTL()
+	now := c.clock.Now()
+	applied := staged[:0]
+	for _, m := range staged {
+		if m.deleted {
+			if _, ok := c.deleteLocked(m.key); !ok {
+				continue
+			}
+		} else {
+			m = c.setNotifiedLocked(m.key, m.value, c.manualEntry(m.value, expiryFor(now, ttl)), now)
+		}
+		applied = append(applied, m)
+	}
+	applied = append(applied, c.evictLocked()...)
+	c.writeBehindStageLocked(applied)
+	h := c.hooks
+	c.mu.Unlock()
+	c.notify(h, applied)
+	return nil
+}
+
+// Get returns the value for key as seen by the transaction: staged changes
+// first, then the current cache contents.
+func (tx *Txn[T]) Get(key string) (T, bool) {
+	key = tx.c.normKey(key)
+	for i := len(tx.staged) - 1; i >= 0; i-- {
+		if m := tx.staged[i]; m.key == key {
+			if m.deleted {
+				var zero T
+				return zero, false
+			}
+			return m.value, true
+		}
+	}
+	return tx.c.Get(key)
+}
+
+// Set stages an insert or update of key.
+func (tx *Txn[T]) Set(key string, value T) {
+	tx.staged = append(tx.staged, mutation[T]{key: tx.c.normKey(key), value: value})
+}
+
+// Delete stages the removal of key.
+func (tx *Txn[T]) Delete(key string) {
+	tx.staged = append(tx.staged, mutation[T]{key: tx.c.normKey(key), deleted: true})
+}
@@ -0,0 +1,156 @@
+package cache_test
+
+import (
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/TheOrchestraX/cache"
+)
+
+func TestTxnReadersSeeAllOrNone(t *testing.T) {
+	c, _ := newTestCache(t, map[string]int{"order": 0, "line1": 0, "line2": 0})
+	var stop atomic.Bool
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for i := 1; i <= 2000; i++ {
+			c.Txn(func(tx *cache.Txn[int]) {
+				tx.Set("order", i)
+				tx.Set("line1", i)
+				tx.Set("line2", i)
+			})
+		}
+		stop.Store(true)
+	}()
+	for !stop.Load() {
+		snap := c.Snapshot()
+		o, _ := snap.Get("order")
+		l1, _ := snap.Get("line1")
+		l2, _ := snap.Get("line2")
+		if o != l1 || o != l2 {
+			t.Fatalf("partial transaction observed: order=%d line1=%d line2=%d", o, l1, l2)
+		}
+	}
+	wg.Wait()
+}
+
+func TestTxnPanicDiscards(t *testing.T) {
+	c, _ := newTestCache(t, map[string]int{"a": 1})
+	func() {
+		defer func() {
+			if recover() == nil {
+				t.Fatal("panic not propagated")
+			}
+		}()
+		c.Txn(func(tx *cache.Txn[int]) {
+			tx.Set("a", 2)
+			tx.Delete("a")
+			tx.Set("b", 3)
+			panic("boom")
+		})
+	}()
+	if got := mustGet(t, c, "a"); got != 1 {
+		t.Fatalf("a = %d after panicking Txn, want 1", got)
+	}
+	mustMiss(t, c, "b")
+}
+
+func TestTxnHooksOncePerMutation(t *testing.T) {
+	c, _ := newTestCache(t, map[string]int{"gone": 1})
+	var adds, deletes []string
+	c.OnAdd(func(key string, _ int) { adds = append(adds, key) })
+	c.OnDelete(func(key string) { deletes = append(deletes, key) })
+	c.Txn(func(tx *cache.Txn[int]) {
+		tx.Set("a", 1)
+		tx.Set("b", 2)
+		tx.Delete("gone")
+		tx.Delete("never-there")
+		if v, ok := tx.Get("a"); !ok || v != 1 {
+			t.Errorf("tx.Get(a) = %d, %v; want staged 1", v, ok)
+		}
+	})
+	if len(adds) != 2 || adds[0] != "a" || adds[1] != "b" {
+		t.Fatalf("OnAdd keys = %v", adds)
+	}
+	if len(deletes) != 1 || deletes[0] != "gone" {
+		t.Fatalf("OnDelete keys = %v", deletes)
+	}
+}
+
+func TestTxnValidatesOutsideLock(t *testing.T) {
+	var c *cache.Cache[int]
+	c = cache.NewCache(func() (map[string]int, error) { return nil, nil }, 0,
+		cache.WithLogLevel[int](cache.LogSilent),
+		cache.WithValueValidator(func(key string, v int) error {
+			// Reading the cache from a validator must not deadlock.
+			if limit, ok := c.Get("limit"); ok && key != "limit" && v > limit {
+				return errors.New("over limit")
+			}
+			return nil
+		}))
+	c.Add("limit", 10)
+	err := c.Txn(func(tx *cache.Txn[int]) {
+		tx.Set("ok", 5)
+		tx.Set("bad", 50)
+	})
+	if !errors.Is(err, cache.ErrInvalidValue) {
+		t.Fatalf("Txn error = %v, want ErrInvalidValue", err)
+	}
+	mustMiss(t, c, "ok")
+	mustMiss(t, c, "bad")
+	if n := c.Stats().Invalid; n != 1 {
+		t.Fatalf("Stats().Invalid = %d, want 1", n)
+	}
+}
+
+func TestTxnRejectedWriteAbortsAll(t *testing.T) {
+	errOdd := errors.New("odd")
+	c, _ := newTestCache(t, map[string]int{"a": 1, "b": 2},
+		cache.WithTransform(func(_ string, v int) (int, error) {
+			if v%2 != 0 {
+				return v, errOdd
+			}
+			return v * 10, nil
+		}))
+	// The loaded odd value was dropped by the transform.
+	before, gen := c.GetAll(), c.Generation()
+	var hooked atomic.Int32
+	c.OnAdd(func(string, int) { hooked.Add(1) })
+	c.OnDelete(func(string) { hooked.Add(1) })
+
+	err := c.Txn(func(tx *cache.Txn[int]) {
+		tx.Set("c", 4)
+		tx.Delete("b")
+		tx.Set("d", 5) // rejected
+		tx.Set("e", 6)
+	})
+	if !errors.Is(err, errOdd) || !errors.Is(err, cache.ErrInvalidValue) {
+		t.Fatalf("Txn error = %v, want ErrInvalidValue wrapping %v", err, errOdd)
+	}
+	if got := c.GetAll(); len(got) != len(before) || got["b"] != before["b"] {
+		t.Fatalf("GetAll = %v after a rejected Txn, want %v", got, before)
+	}
+	if c.Generation() != gen || hooked.Load() != 0 {
+		t.Fatalf("rejected Txn advanced Generation or fired hooks")
+	}
+
+	// A transaction whose writes all pass is applied, transformed.
+	if err := c.Txn(func(tx *cache.Txn[int]) { tx.Set("c", 4) }); err != nil {
+		t.Fatal(err)
+	}
+	if v := mustGet(t, c, "c"); v != 40 {
+		t.Fatalf("Get(c) = %d, want 40", v)
+	}
+}
+
+func TestTxnFrozen(t *testing.T) {
+	c, _ := newTestCache(t, map[string]int{"a": 1})
+	c.Freeze()
+	if err := c.Txn(func(tx *cache.Txn[int]) { tx.Delete("a") }); !errors.Is(err, cache.ErrFrozen) {
+		t.Fatalf("Txn error = %v, want ErrFrozen", err)
+	}
+	mustGet(t, c, "a")
+}
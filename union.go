@@ -0,0 +1,121 @@
+package cache
+
+// union is a ReadView over several caches, the earlier taking precedence.
+type union[T any] struct {
+	caches []*Cache[T]
+}
+
+var _ ReadView[int] = (*union[int])(nil)
+
+// Union returns a read view over the logical union of caches, such as
+// per-region overrides layered on global defaults. A key present in more
+// than one cache is reported once, with the value from the earliest of
+// them. The view copies nothing and reads the caches live, so it observes
+// their reloads and writes; mutations go to the caches themselves. Each
+// cache is read separately, so a view spanning a reload of one of them
+// may mix that cache's old and new data.
+func Union[T any](caches ...*Cache[T]) ReadView[T] {
+	return &union[T]{caches: caches}
+}
+
+// Get returns the item for key from the first cache holding it. Each cache
+// consulted counts a hit or a miss in its own statistics.
+func (u *union[T]) Get(key string) (T, bool) {
+	for _, c := range u.caches {
+		if v, ok := c.Get(key); ok {
+			return v, true
+		}
+	}
+	var zero T
+	return zero, false
+}
+
+// Has reports whether any of the caches holds key, stopping at the first
+// that does. Like Cache.Has, it counts no hits or misses.
+func (u *union[T]) Has(key string) bool {
+	for _, c := range u.caches {
+		if c.Has(key) {
+			return true
+		}
+	}
+	return false
+}
+
+// GetAll returns the merged items of the view.
+func (u *union[T]) GetAll() map[string]T {
+	all := make(map[string]T)
+	u.Range(func(k string, v T) bool {
+		all[k] = v
+		return true
+	})
+	return all
+}
+
+// Find returns all items of the view satisfying predicate.
+func (u *union[T]) Find(predicate func(T) bool) []T {
+	var found []T
+	u.Range(func(_ string, v T) bool {
+		if predicate(v) {
+			found = append(found, v)
+		}
+		return true
+	})
+	return found
+}
+
+// FindOne returns the first item of the view satisfying predicate, or
+// false if none, checking the caches in order.
+func (u *union[T]) FindOne(predicate func(T) bool) (T, bool) {
+	var found T
+	var ok bool
+	u.Range(func(_ string, v T) bool {
+		if predicate(v) {
+			found, ok = v, true
+		}
+		return !ok
+	})
+	return found, ok
+}
+
+// Len returns the number of distinct keys in the view.
+func (u *union[T]) Len() int {
+	n := 0
+	u.Range(func(string, T) bool {
+		n++
+		return true
+	})
+	return n
+}
+
+// Keys returns the distinct keys of the view, in no particular order.
+func (u *union[T]) Keys() []string {
+	var keys []string
+	u.Range(func(k string, _ T) bool {
+		keys = append(keys, k)
+		return true
+	})
+	return keys
+}
+
+// Range calls fn for each item of the view until fn returns false, ranging
+// over the caches in order and skipping keys already reported by an
+// earlier one. See Cache.Range.
+func (u *union[T]) Range(fn func(key string, value T) bool) {
+	seen := make(map[string]struct{})
+	for i, c := range u.caches {
+		more := true
+		c.Range(func(k string, v T) bool {
+			if _, dup := seen[k]; dup {
+				return true
+			}
+			if i < len(u.caches)-1 {
+				seen[k] = struct{}{}
+			}
+			more = fn(k, v)
+			return more
+		})
+		if !more {
+			return
+		}
+	}
+}
@@ -0,0 +1,53 @@
+package cache_test
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/TheOrchestraX/cache"
+)
+
+func TestUnionFirstHitWins(t *testing.T) {
+	region, _ := newTestCache(t, map[string]string{"timeout": "5s"})
+	global, _ := newTestCache(t, map[string]string{"timeout": "30s", "theme": "dark"})
+	u := cache.Union(region, global)
+
+	if v, ok := u.Get("timeout"); !ok || v != "5s" {
+		t.Fatalf("Get(timeout) = %q, %v; want the region's 5s", v, ok)
+	}
+	if v, ok := u.Get("theme"); !ok || v != "dark" {
+		t.Fatalf("Get(theme) = %q, %v; want dark", v, ok)
+	}
+	if !u.Has("timeout") || !u.Has("theme") || u.Has("missing") {
+		t.Fatal("Has disagrees with the union's contents")
+	}
+	keys := u.Keys()
+	slices.Sort(keys)
+	if !slices.Equal(keys, []string{"theme", "timeout"}) || u.Len() != 2 {
+		t.Fatalf("Keys = %v, Len = %d; want each key once", keys, u.Len())
+	}
+	if all := u.GetAll(); all["timeout"] != "5s" {
+		t.Fatalf("GetAll = %v, want the region's timeout", all)
+	}
+
+	// The view reads live.
+	region.Delete("timeout")
+	if v, _ := u.Get("timeout"); v != "30s" {
+		t.Fatalf("Get(timeout) after delete = %q, want the global 30s", v)
+	}
+}
+
+func TestHasCountsNoLookups(t *testing.T) {
+	c, _ := newTestCache(t, map[string]int{"a": 1})
+	for _, v := range []cache.ReadView[int]{c.ReadOnly(), c.WithOverrides(map[string]int{"b": 2}), cache.Union(c)} {
+		if !v.Has("a") || v.Has("z") {
+			t.Fatalf("%T: Has disagrees with Get", v)
+		}
+	}
+	if !c.WithOverrides(map[string]int{"b": 2}).Has("b") {
+		t.Fatal("overlay.Has misses an override")
+	}
+	if s := c.Stats(); s.Hits != 0 || s.Misses != 0 {
+		t.Fatalf("Stats = %d hits, %d misses; want none from Has", s.Hits, s.Misses)
+	}
+}
@@ -0,0 +1,196 @@
+package cache
+
+import (
+	"fmt"
+	"maps"
+	"reflect"
+)
+
+// InvalidPolicy selects what a load does with values rejected by
+// WithTransform, WithValueValidator or WithRejectNil.
+type InvalidPolicy int
+
+const (
+	// DropInvalid installs the load without the rejected items and logs a
+	// warning. It is the default.
+	DropInvalid InvalidPolicy = iota
+	// FailLoad fails the whole load, keeping the previous data, as if the
+	// loader had returned an error.
+	FailLoad
+)
+
+// String returns the policy's name.
+func (p InvalidPolicy) String() string {
+	switch p {
+	case DropInvalid:
+		return "drop-invalid"
+	case FailLoad:
+		return "fail-load"
+	}
+	return fmt.Sprintf("InvalidPolicy(%d)", int(p))
+}
+
+// WithValueValidator checks every value before it is stored, by a load or
+// by a write such as Add. A non-nil error rejects the value: writes drop it
+// (AddE returns the error) and loads follow WithInvalidPolicy. Rejections
+// are counted in Stats.Invalid.
+func WithValueValidator[T any](fn func(key string, value T) error) Option[T] {
+	return func(c *Cache[T]) {
+		c.validator = fn
+	}
+}
+
+// WithRejectNil rejects nil values, such as nil pointers, maps or slices,
+// as WithValueValidator would. It combines with a validator.
+func WithRejectNil[T any](enabled bool) Option[T] {
+	return func(c *Cache[T]) {
+		c.rejectNil = enabled
+	}
+}
+
+// WithTransform post-processes every value before it is stored, by a load
+// or by a write such as Add, for work such as parsing a raw field that
+// would otherwise be repeated on every read. It runs before any
+// WithValueValidator, which sees the transformed value. An error rejects
+// the value as an invalid one: writes drop it, loads follow
+// WithInvalidPolicy, and it is counted in Stats.Invalid. Transforming is
+// part of a load, so its time is included in the load duration.
+func WithTransform[T any](fn func(key string, raw T) (T, error)) Option[T] {
+	return func(c *Cache[T]) {
+		c.transform = fn
+	}
+}
+
+// WithInvalidPolicy sets what loads do with rejected values; see
+// InvalidPolicy. Under WithChunkedReload, chunks installed before a
+// rejected value is reached stay installed when the load fails.
+func WithInvalidPolicy[T any](p InvalidPolicy) Option[T] {
+	return func(c *Cache[T]) {
+		c.invalidPolicy = p
+	}
+}
+
+// checksValues reports whether values are transformed or validated before
+// being stored.
+func (c *Cache[T]) checksValues() bool {
+	return c.transform != nil || c.validator != nil || c.rejectNil
+}
+
+// validate transforms and checks value, to be stored under key, returning
+// the value to store, or an error wrapping ErrInvalidValue if it is
+// rejected. Rejections are counted in Stats.Invalid.
+func (c *Cache[T]) validate(key string, value T) (T, error) {
+	value, err := c.check(key, value)
+	if err != nil {
+		c.stats.invalid.Add(1)
+	}
+	return value, err
+}
+
+// check is validate without the counting.
+func (c *Cache[T]) check(key string, value T) (T, error) {
+	if !c.checksValues() {
+		return value, nil
+	}
+	if c.transform != nil {
+		var err error
+		if value, err = c.transform(key, value); err != nil {
+			return value, fmt.Errorf("%w: key %q: transform: %w", ErrInvalidValue, key, err)
+		}
+	}
+	if c.rejectNil && isNil(value) {
+		return value, fmt.Errorf("%w: key %q: nil value", ErrInvalidValue, key)
+	}
+	if c.validator != nil {
+		if err := c.validator(key, value); err != nil {
+			return value, fmt.Errorf("%w: key %q: %w", ErrInvalidValue, key, err)
+		}
+	}
+	return value, nil
+}
+
+// validWrite validates a value about to be written, logging a rejection.
+// It returns the value to store and whether to store it.
+func (c *Cache[T]) validWrite(key string, value T) (T, bool) {
+	value, err := c.validate(key, value)
+	if err != nil {
+		c.logf(LogErrorsOnly, "%s rejected write: %v", c.label(), err)
+		return value, false
+	}
+	return value, true
+}
+
+// invalidDrops tallies the values dropped from one load, to be logged once.
+type invalidDrops struct {
+	n     int
+	first error
+}
+
+// checkLoaded validates a loaded value. It returns the value to store and
+// whether to keep it, or an error if the load must fail.
+func (c *Cache[T]) checkLoaded(key string, value T, drops *invalidDrops) (T, bool, error) {
+	value, err := c.validate(key, value)
+	switch {
+	case err == nil:
+		return value, true, nil
+	case c.invalidPolicy == FailLoad:
+		return value, false, err
+	}
+	drops.n++
+	if drops.first == nil {
+		drops.first = err
+	}
+	return value, false, nil
+}
+
+// logDrops reports the values dropped from a load.
+func (c *Cache[T]) logDrops(drops invalidDrops) {
+	if drops.n > 0 {
+		c.logf(LogErrorsOnly, "%s load dropped %d invalid values; first: %v", c.label(), drops.n, drops.first)
+	}
+}
+
+// validItems transforms and validates loaded items, returning the items to
+// store, or an error if the load must fail. Without a transform, items is
+// only copied if something is dropped.
+func (c *Cache[T]) validItems(items map[string]T) (map[string]T, error) {
+	if !c.checksValues() {
+		return items, nil
+	}
+	var drops invalidDrops
+	var valid map[string]T
+	if c.transform != nil {
+		valid = make(map[string]T, len(items))
+	}
+	for k, v := range items {
+		v, keep, err := c.checkLoaded(k, v, &drops)
+		switch {
+		case err != nil:
+			return nil, err
+		case keep && c.transform != nil:
+			valid[k] = v
+		case !keep && c.transform == nil:
+			if valid == nil {
+				valid = maps.Clone(items)
+			}
+			delete(valid, k)
+		}
+	}
+	c.logDrops(drops)
+	if valid == nil {
+		return items, nil
+	}
+	return valid, nil
+}
+
+// isNil reports whether v is nil, for the kinds that can be.
+func isNil(v any) bool {
+	if v == nil {
+		return true
+	}
+	switch rv := reflect.ValueOf(v); rv.Kind() {
+	case reflect.Pointer, reflect.Map, reflect.Slice, reflect.Interface, reflect.Func, reflect.Chan:
+		return rv.IsNil()
+	}
+	return false
+}
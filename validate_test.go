@@ -0,0 +1,106 @@
+package cache_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/TheOrchestraX/cache"
+)
+
+var errNegative = errors.New("negative")
+
+func nonNegative(key string, v int) error {
+	if v < 0 {
+		return errNegative
+	}
+	return nil
+}
+
+// exported returns the Export output of a cache holding items, bypassing
+// validation.
+func exported(t *testing.T, items map[string]int) string {
+	t.Helper()
+	src, _ := newTestCache(t, items)
+	var buf bytes.Buffer
+	if err := src.Export(&buf); err != nil {
+		t.Fatalf("Export: %v", err)
+	}
+	return buf.String()
+}
+
+func TestStorePathsDropInvalid(t *testing.T) {
+	items := map[string]int{"ok": 1, "bad": -1}
+	blob, err := json.Marshal(items)
+	if err != nil {
+		t.Fatal(err)
+	}
+	dump := exported(t, items)
+	paths := map[string]func(c *cache.Cache[int]) error{
+		"Prime":         func(c *cache.Cache[int]) error { c.Prime(items); return nil },
+		"Import":        func(c *cache.Cache[int]) error { _, err := c.Import(strings.NewReader(dump)); return err },
+		"UnmarshalJSON": func(c *cache.Cache[int]) error { return c.UnmarshalJSON(blob) },
+	}
+	for name, store := range paths {
+		t.Run(name, func(t *testing.T) {
+			c, _ := newTestCache(t, map[string]int{}, cache.WithValueValidator(nonNegative))
+			if err := store(c); err != nil {
+				t.Fatalf("%s: %v", name, err)
+			}
+			mustGet(t, c, "ok")
+			mustMiss(t, c, "bad")
+			if n := c.Stats().Invalid; n != 1 {
+				t.Fatalf("Stats().Invalid = %d, want 1", n)
+			}
+		})
+	}
+}
+
+func TestStorePathsFailLoadKeepContents(t *testing.T) {
+	items := map[string]int{"ok": 1, "bad": -1}
+	blob, err := json.Marshal(items)
+	if err != nil {
+		t.Fatal(err)
+	}
+	dump := exported(t, items)
+	c, _ := newTestCache(t, map[string]int{"old": 1},
+		cache.WithValueValidator(nonNegative),
+		cache.WithInvalidPolicy[int](cache.FailLoad))
+	c.Prime(items)
+	if _, err := c.Import(strings.NewReader(dump)); !errors.Is(err, errNegative) {
+		t.Fatalf("Import = %v, want errNegative", err)
+	}
+	if err := c.UnmarshalJSON(blob); !errors.Is(err, errNegative) {
+		t.Fatalf("UnmarshalJSON = %v, want errNegative", err)
+	}
+	mustGet(t, c, "old")
+	mustMiss(t, c, "ok")
+}
+
+func TestStorePathsTransform(t *testing.T) {
+	items := map[string]int{"a": 1}
+	blob, err := json.Marshal(items)
+	if err != nil {
+		t.Fatal(err)
+	}
+	dump := exported(t, items)
+	double := cache.WithTransform(func(key string, v int) (int, error) { return 2 * v, nil })
+	paths := map[string]func(c *cache.Cache[int]) error{
+		"Prime":         func(c *cache.Cache[int]) error { c.Prime(items); return nil },
+		"Import":        func(c *cache.Cache[int]) error { _, err := c.Import(strings.NewReader(dump)); return err },
+		"UnmarshalJSON": func(c *cache.Cache[int]) error { return c.UnmarshalJSON(blob) },
+	}
+	for name, store := range paths {
+		t.Run(name, func(t *testing.T) {
+			c, _ := newTestCache(t, map[string]int{}, double)
+			if err := store(c); err != nil {
+				t.Fatalf("%s: %v", name, err)
+			}
+			if v := mustGet(t, c, "a"); v != 2 {
+				t.Fatalf("Get(a) = %d, want the transformed 2", v)
+			}
+		})
+	}
+}
@@ -0,0 +1,51 @@
+package cache
+
+import (
+	"reflect"
+	"strconv"
+)
+
+// WithValueHasher maintains an index of items by fn(value), making
+// KeysForValue a lookup instead of a scan. Like the secondary indexes it
+// is rebuilt on every Load and updated on Add and Delete. Values whose
+// hashes collide are told apart by the equality func given to
+// KeysForValue.
+func WithValueHasher[T any](fn func(T) uint64) Option[T] {
+	return func(c *Cache[T]) {
+		c.valueIndex = &index[T]{
+			keysFn: func(v T) []string { return []string{strconv.FormatUint(fn(v), 16)} },
+			byKey:  make(map[string]map[string]struct{}),
+		}
+	}
+}
+
+// KeysForValue returns the keys of the live items equal to value per eq,
+// or per reflect.DeepEqual if eq is nil, in no particular order. It scans
+// every item under the read lock unless WithValueHasher is set, in which
+// case only the items with value's hash are compared.
+func (c *Cache[T]) KeysForValue(value T, eq func(a, b T) bool) []string {
+	if eq == nil {
+		eq = func(a, b T) bool { return reflect.DeepEqual(a, b) }
+	}
+	now := c.clock.Now()
+	c.clearExpiredData(now)
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	var keys []string
+	if c.valueIndex != nil {
+		for _, h := range c.valueIndex.keysFn(value) {
+			for k := range c.valueIndex.byKey[h] {
+				if e := c.data[k]; !e.expired(now) && eq(e.val(), value) {
+					keys = append(keys, k)
+				}
+			}
+		}
+		return keys
+	}
+	for k, e := range c.data {
+		if !e.expired(now) && eq(e.val(), value) {
+			keys = append(keys, k)
+		}
+	}
+	return keys
+}
@@ -0,0 +1,70 @@
+package cache
+
+import "fmt"
+
+// GetVersioned returns the item for key along with its version, for use as
+// an ETag or in a later AddIfVersion. Versions start at 1 when a key is
+// first stored and increase by one on every write to it; a reload bumps
+// only the entries whose value changed, as judged by WithEqual (without it,
+// every reloaded entry counts as changed). Like GetWithMeta, it neither
+// counts towards hit/miss statistics nor extends a sliding expiry. key may
+// be an alias.
+func (c *Cache[T]) GetVersioned(key string) (T, uint64, bool) {
+	key = c.normKey(key)
+	now := c.clock.Now()
+	c.clearExpiredData(now)
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	e, ok := c.data[c.resolveLocked(key)]
+	if !ok || e.expired(now) {
+		var zero T
+		return zero, 0, false
+	}
+	return c.output(e.val()), e.version, true
+}
+
+// AddIfVersion stores value under key only if the key's current version is
+// expectedVersion, as a conditional update. An expectedVersion of 0 means
+// the key must be absent. On a mismatch nothing is stored and an error
+// wrapping ErrVersionMismatch is returned. An alias checks and updates the
+// item it refers to.
+func (c *Cache[T]) AddIfVersion(key string, value T, expectedVersion uint64) error {
+	key = c.normKey(key)
+	value, err := c.validate(key, value)
+	if err != nil {
+		return err
+	}
+	if c.rejectFrozen("add") {
+		return ErrFrozen
+	}
+	now := c.clock.Now()
+	c.mu.Lock()
+	key = c.resolveLocked(key)
+	var current uint64
+	if e, ok := c.data[key]; ok && !e.expired(now) {
+		current = e.version
+	}
+	if current != expectedVersion {
+		c.mu.Unlock()
+		return fmt.Errorf("%w: key %q is at version %d, not %d", ErrVersionMismatch, key, current, expectedVersion)
+	}
+	m := c.setNotifiedLocked(key, value, c.manualEntry(value, expiryFor(now, c.addTTL())), now)
+	muts := append([]mutation[T]{m}, c.evictLocked()...)
+	c.writeBehindStageLocked(muts)
+	h := c.hooks
+	c.mu.Unlock()
+	c.notify(h, muts)
+	return nil
+}
+
+// nextVersion returns the version for e, about to replace old (nil if the
+// key is new). Loaded values equal to the old ones keep its version.
+func (c *Cache[T]) nextVersion(old, e *entry[T]) uint64 {
+	if old == nil {
+		return 1
+	}
+	if e.source == SourceLoader && c.equal != nil && c.equal(old.val(), e.val()) {
+		return old.version
+	}
+	return old.version + 1
+}
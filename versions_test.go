@@ -0,0 +1,45 @@
+package cache_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/TheOrchestraX/cache"
+)
+
+// byEmail is an alias function for items whose value is an email address.
+func byEmail(email string) []string { return []string{email} }
+
+func TestGetVersionedResolvesAlias(t *testing.T) {
+	c, _ := newTestCache(t, map[string]string{"u1": "alice@example.com"},
+		cache.WithAliases(byEmail))
+	c.Add("u1", "alice@example.com")
+
+	v, ver, ok := c.GetVersioned("alice@example.com")
+	if !ok || v != "alice@example.com" || ver != 2 {
+		t.Fatalf("GetVersioned(alias) = %q, %d, %v; want the item at version 2", v, ver, ok)
+	}
+	if _, _, ok := c.GetVersioned("bob@example.com"); ok {
+		t.Fatal("GetVersioned of an unknown alias hit")
+	}
+}
+
+func TestAddIfVersionResolvesAlias(t *testing.T) {
+	c, _ := newTestCache(t, map[string]string{"u1": "alice@example.com"},
+		cache.WithAliases(byEmail))
+
+	if err := c.AddIfVersion("alice@example.com", "alice@example.com", 0); !errors.Is(err, cache.ErrVersionMismatch) {
+		t.Fatalf("AddIfVersion(alias, 0) = %v, want ErrVersionMismatch: the alias names an existing item", err)
+	}
+	if err := c.AddIfVersion("alice@example.com", "alice@example.org", 1); err != nil {
+		t.Fatalf("AddIfVersion(alias, 1) = %v", err)
+	}
+	if v, ver, ok := c.GetVersioned("u1"); !ok || v != "alice@example.org" || ver != 2 {
+		t.Fatalf("GetVersioned(u1) = %q, %d, %v; want the update at version 2", v, ver, ok)
+	}
+	if n := c.Len(); n != 1 {
+		t.Fatalf("Len = %d, want 1: the write through the alias must not add an item", n)
+	}
+	mustGet(t, c, "alice@example.org")
+	mustMiss(t, c, "alice@example.com")
+}
@@ -0,0 +1,67 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// Warmable is a cache that can be loaded during startup warm-up. *Cache[T]
+// implements it for every T.
+type Warmable interface {
+	Name() string
+	LoadContext(ctx context.Context) error
+}
+
+// WarmUpConfig tunes WarmUp. The zero value runs every load concurrently
+// and treats any failure as fatal.
+type WarmUpConfig struct {
+	// MaxConcurrency bounds the number of simultaneous loads; <= 0 means
+	// no limit.
+	MaxConcurrency int
+	// ContinueOnError reports individual failures to OnError instead of
+	// returning them, so startup proceeds with whichever caches loaded.
+	// A *Cache also logs its own load failures through its logger.
+	ContinueOnError bool
+	// OnError, if set, is called with the cache's name and error for each
+	// load that fails under ContinueOnError. Calls are serialized.
+	OnError func(name string, err error)
+	// Progress, if set, is called after each load finishes with the
+	// cache's name and the number of loads done so far out of total.
+	// Calls are serialized.
+	Progress func(name string, done, total int)
+}
+
+// WarmUp runs the initial load of every cache concurrently and returns once
+// all have finished, with their failures joined via errors.Join. Each load
+// runs under ctx via LoadContext. Once ctx is done no further loads are
+// started and ctx's error is returned; loads already running are waited
+// for. Unnamed caches are reported by position.
+func WarmUp(ctx context.Context, cfg WarmUpConfig, caches ...Warmable) error {
+	loads := make([]namedLoad, len(caches))
+	for i, c := range caches {
+		name := c.Name()
+		if name == "" {
+			name = fmt.Sprintf("cache #%d", i)
+		}
+		loads[i] = namedLoad{name: name, load: c.LoadContext}
+	}
+	done := 0
+	err := runLoads(ctx, loads, cfg.MaxConcurrency, func(name string, err error) {
+		done++
+		if err != nil && cfg.ContinueOnError && cfg.OnError != nil {
+			cfg.OnError(name, err)
+		}
+		if cfg.Progress != nil {
+			cfg.Progress(name, done, len(loads))
+		}
+	})
+	if cfg.ContinueOnError {
+		// Only an aborted warm-up is fatal in this mode.
+		if ctxErr := ctx.Err(); ctxErr != nil && errors.Is(err, ctxErr) {
+			return ctxErr
+		}
+		return nil
+	}
+	return err
+}
@@ -0,0 +1,61 @@
+package cache_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/TheOrchestraX/cache"
+)
+
+func TestWarmUpContinueOnError(t *testing.T) {
+	boom := errors.New("boom")
+	good := cache.NewCache(func() (map[string]int, error) { return map[string]int{"a": 1}, nil }, 0,
+		cache.WithName[int]("good"), cache.WithLogLevel[int](cache.LogSilent))
+	bad := cache.NewCache(func() (map[string]int, error) { return nil, boom }, 0,
+		cache.WithName[int]("bad"), cache.WithLogLevel[int](cache.LogSilent))
+
+	if err := cache.WarmUp(context.Background(), cache.WarmUpConfig{}, good, bad); !errors.Is(err, boom) {
+		t.Fatalf("WarmUp = %v, want boom", err)
+	}
+
+	var failed []string
+	var progress int
+	cfg := cache.WarmUpConfig{
+		ContinueOnError: true,
+		OnError: func(name string, err error) {
+			if !errors.Is(err, boom) {
+				t.Errorf("OnError(%s, %v), want boom", name, err)
+			}
+			failed = append(failed, name)
+		},
+		Progress: func(name string, done, total int) {
+			progress++
+			if done != progress || total != 2 {
+				t.Errorf("Progress(%s, %d, %d), want (%d, 2)", name, done, total, progress)
+			}
+		},
+	}
+	if err := cache.WarmUp(context.Background(), cfg, good, bad); err != nil {
+		t.Fatalf("WarmUp with ContinueOnError = %v", err)
+	}
+	if len(failed) != 1 || failed[0] != "bad" || progress != 2 {
+		t.Fatalf("failed = %v, progress = %d; want [bad], 2", failed, progress)
+	}
+	mustGet(t, good, "a")
+}
+
+func TestWarmUpPassesContext(t *testing.T) {
+	var got any
+	c := cache.NewCacheWithContext(func(ctx context.Context) (map[string]int, error) {
+		got = ctx.Value(ctxKey{})
+		return map[string]int{}, nil
+	}, 0, cache.WithLogLevel[int](cache.LogSilent))
+	ctx := context.WithValue(context.Background(), ctxKey{}, "warm")
+	if err := cache.WarmUp(ctx, cache.WarmUpConfig{}, c); err != nil {
+		t.Fatalf("WarmUp: %v", err)
+	}
+	if got != "warm" {
+		t.Fatalf("loader saw %v, want warm", got)
+	}
+}
@@ -0,0 +1,347 @@
+package cache
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// OpKind is the kind of a write-behind Op.
+type OpKind int
+
+const (
+	// OpSet stores Value under Key.
+	OpSet OpKind = iota
+	// OpDelete removes Key.
+	OpDelete
+)
+
+// String returns the kind's name.
+func (k OpKind) String() string {
+	switch k {
+	case OpSet:
+		return "set"
+	case OpDelete:
+		return "delete"
+	}
+	return fmt.Sprintf("OpKind(%d)", int(k))
+}
+
+// Op is a cache mutation handed to a WithWriteBehind sink.
+type Op[T any] struct {
+	Kind  OpKind
+	Key   string
+	Value T // the stored value, for OpSet
+}
+
+// WriteBehindOverflow selects what a write does when the write-behind
+// buffer is full.
+type WriteBehindOverflow int
+
+const (
+	// BlockWhenFull makes the write wait until the sink has drained some of
+	// the buffer. It is the default. A sink failing persistently therefore
+	// stalls writers once the buffer fills.
+	BlockWhenFull WriteBehindOverflow = iota
+	// DropWhenFull keeps the write in the cache but drops its op, logging
+	// it and counting it in Stats.WriteBehindDropped. The next
+	// FlushWriteBehind reports the drops with ErrWriteBehindDropped.
+	DropWhenFull
+)
+
+// String returns the policy's name.
+func (p WriteBehindOverflow) String() string {
+	switch p {
+	case BlockWhenFull:
+		return "block"
+	case DropWhenFull:
+		return "drop"
+	}
+	return fmt.Sprintf("WriteBehindOverflow(%d)", int(p))
+}
+
+const (
+	defaultWriteBehindInterval = time.Second
+	defaultWriteBehindBatch    = 100
+	// writeBehindBufferBatches is the default buffer size, in batches.
+	writeBehindBufferBatches = 16
+	// maxWriteBehindBackoff caps the retry delay after sink errors, as a
+	// multiple of the flush interval.
+	maxWriteBehindBackoff = 32
+)
+
+// writeBehind is the state behind WithWriteBehind.
+type writeBehind[T any] struct {
+	sink     func(ctx context.Context, ops []Op[T]) error
+	interval time.Duration
+	maxBatch int
+	size     int // buffer bound; 0 means writeBehindBufferBatches batches
+	overflow WriteBehindOverflow
+
+	sinkMu sync.Mutex // held while calling sink, so batches go out in order
+
+	mu      sync.Mutex
+	space   *sync.Cond // signalled when ops leave the buffer
+	ops     []Op[T]
+	running bool          // the flush goroutine is active
+	kick    chan struct{} // wakes the flush goroutine early
+	dropped int           // ops dropped since the last FlushWriteBehind
+
+	// unlogged holds the kind and key of dropped ops not yet logged, as
+	// they are dropped under c.mu and logged after it is released.
+	unlogged []Op[T]
+}
+
+// WithWriteBehind mirrors the cache's own mutations to sink, such as a
+// database, without making writers wait for it. Every change that fires
+// OnAdd or OnDelete, from Add, Delete, Txn and the like, is queued as an
+// Op. A background goroutine hands the queue to sink in order, in batches
+// of up to maxBatch, every flushInterval or as soon as a batch is full.
+// Loads, evictions, expiry and Clear are not mirrored.
+//
+// A batch that sink fails is kept at the head of the queue and retried,
+// with the delay doubling after each failure up to 32 flushIntervals, so
+// no op is lost. sink may therefore see a batch more than once. The
+// buffer is bounded; see WithWriteBehindBuffer. The goroutine only runs
+// while ops are queued. Ops are queued under the cache lock, so they reach
+// sink in the order the mutations were applied, even for concurrent writes
+// to one key. Call Close, StopAndWait or FlushWriteBehind at shutdown to
+// drain the queue. A flushInterval <= 0 means one second and a
+// maxBatch <= 0 means 100.
+func WithWriteBehind[T any](sink func(ctx context.Context, ops []Op[T]) error, flushInterval time.Duration, maxBatch int) Option[T] {
+	return func(c *Cache[T]) {
+		w := c.writeBehindConfig()
+		w.sink = sink
+		w.interval = flushInterval
+		if w.interval <= 0 {
+			w.interval = defaultWriteBehindInterval
+		}
+		w.maxBatch = maxBatch
+		if w.maxBatch <= 0 {
+			w.maxBatch = defaultWriteBehindBatch
+		}
+	}
+}
+
+// WithWriteBehindBuffer bounds the WithWriteBehind queue at size ops and
+// sets what writes do when it is full. By default the queue holds 16
+// batches and writes block when it is full.
+func WithWriteBehindBuffer[T any](size int, overflow WriteBehindOverflow) Option[T] {
+	return func(c *Cache[T]) {
+		w := c.writeBehindConfig()
+		w.size = max(size, 0)
+		w.overflow = overflow
+	}
+}
+
+// writeBehindConfig returns the write-behind state, creating it for the
+// options that configure it.
+func (c *Cache[T]) writeBehindConfig() *writeBehind[T] {
+	if c.wb == nil {
+		c.wb = &writeBehind[T]{kick: make(chan struct{}, 1)}
+		c.wb.space = sync.NewCond(&c.wb.mu)
+	}
+	return c.wb
+}
+
+// capacity returns the buffer bound.
+func (w *writeBehind[T]) capacity() int {
+	if w.size > 0 {
+		return w.size
+	}
+	return writeBehindBufferBatches * w.maxBatch
+}
+
+// writeBehindStageLocked queues the mutations in muts for the sink, if
+// WithWriteBehind is set. It runs under c.mu, so ops are queued in the
+// order their mutations were applied. It never blocks: under BlockWhenFull
+// the queue may briefly exceed its bound, and writeBehindWait holds the
+// writer back once c.mu is released. The caller must hold c.mu for
+// writing.
+func (c *Cache[T]) writeBehindStageLocked(muts []mutation[T]) {
+	w := c.wb
+	if w == nil || w.sink == nil {
+		return
+	}
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	for _, m := range muts {
+		if m.evicted {
+			continue
+		}
+		op := Op[T]{Kind: OpSet, Key: m.key, Value: m.value}
+		if m.deleted {
+			op = Op[T]{Kind: OpDelete, Key: m.key}
+		}
+		if w.overflow == DropWhenFull && len(w.ops) >= w.capacity() {
+			w.dropped++
+			c.stats.writeBehindDropped.Add(1)
+			w.unlogged = append(w.unlogged, Op[T]{Kind: op.Kind, Key: op.Key})
+			continue
+		}
+		w.ops = append(w.ops, op)
+	}
+	if len(w.ops) == 0 {
+		return
+	}
+	if !w.running {
+		w.running = true
+		go c.writeBehindLoop()
+	}
+	if len(w.ops) >= w.maxBatch {
+		select {
+		case w.kick <- struct{}{}:
+		default:
+		}
+	}
+}
+
+// writeBehindWait finishes a write staged by writeBehindStageLocked: it
+// logs dropped ops and, under BlockWhenFull, waits until the queue is back
+// within its bound. It must be called without holding c.mu.
+func (c *Cache[T]) writeBehindWait() {
+	w := c.wb
+	if w == nil || w.sink == nil {
+		return
+	}
+	w.mu.Lock()
+	dropped := w.unlogged
+	w.unlogged = nil
+	if w.overflow == BlockWhenFull {
+		for len(w.ops) > w.capacity() {
+			w.space.Wait()
+		}
+	}
+	w.mu.Unlock()
+	for _, op := range dropped {
+		c.logf(LogErrorsOnly, "%s write-behind buffer full; dropped %s of key %q", c.label(), op.Kind, op.Key)
+	}
+}
+
+// writeBehindLoop is the flush goroutine. It hands batches to the sink
+// until the queue is empty, waiting a flush interval between batches
+// unless one is already full, and backing off after sink errors.
+func (c *Cache[T]) writeBehindLoop() {
+	w := c.wb
+	delay := w.interval
+	timer := c.clock.NewTimer(delay)
+	defer timer.Stop()
+	for {
+		kick := w.kick
+		if delay > w.interval {
+			kick = nil // backing off; a full batch must not cut it short
+		}
+		select {
+		case <-timer.C():
+		case <-kick:
+		}
+		n, err := c.writeBehindFlushOne(context.Background())
+		switch {
+		case err != nil:
+			c.stats.writeBehindErrors.Add(1)
+			delay = min(delay*2, maxWriteBehindBackoff*w.interval)
+			c.logf(LogErrorsOnly, "%s write-behind sink failed; retrying in %s: %v", c.label(), delay, err)
+		default:
+			delay = w.interval
+		}
+		w.mu.Lock()
+		if len(w.ops) == 0 {
+			w.running = false
+			w.mu.Unlock()
+			return
+		}
+		full := len(w.ops) >= w.maxBatch
+		w.mu.Unlock()
+		wait := delay
+		if err == nil && full && n > 0 {
+			wait = 0
+		}
+		if !timer.Stop() {
+			select {
+			case <-timer.C():
+			default:
+			}
+		}
+		timer.Reset(wait)
+	}
+}
+
+// writeBehindFlushOne hands the batch at the head of the queue to the sink,
+// removing it on success, and returns its size.
+func (c *Cache[T]) writeBehindFlushOne(ctx context.Context) (int, error) {
+	w := c.wb
+	w.sinkMu.Lock()
+	defer w.sinkMu.Unlock()
+	w.mu.Lock()
+	batch := append([]Op[T](nil), w.ops[:min(len(w.ops), w.maxBatch)]...)
+	w.mu.Unlock()
+	if len(batch) == 0 {
+		return 0, nil
+	}
+	if err := w.sink(ctx, batch); err != nil {
+		return 0, err
+	}
+	// Only sinkMu's holder removes ops, and only from the head, so the
+	// batch is still there.
+	w.mu.Lock()
+	clear(w.ops[:len(batch)])
+	w.ops = w.ops[len(batch):]
+	w.space.Broadcast()
+	w.mu.Unlock()
+	return len(batch), nil
+}
+
+// FlushWriteBehind hands every queued WithWriteBehind op to the sink now,
+// in order, and returns once the queue is empty, for use at shutdown. If
+// the sink fails, the unsent ops stay queued for the background retries
+// and the error is returned. If ctx is done first, its error is returned.
+// Once the queue is drained, ops dropped by DropWhenFull since the last
+// call are reported with an error wrapping ErrWriteBehindDropped. Without
+// WithWriteBehind it does nothing.
+func (c *Cache[T]) FlushWriteBehind(ctx context.Context) error {
+	w := c.wb
+	if w == nil || w.sink == nil {
+		return nil
+	}
+	for {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		n, err := c.writeBehindFlushOne(ctx)
+		if err != nil {
+			return fmt.Errorf("cache: write-behind flush: %w", err)
+		}
+		if n == 0 {
+			break
+		}
+	}
+	w.mu.Lock()
+	dropped := w.dropped
+	w.dropped = 0
+	w.mu.Unlock()
+	if dropped > 0 {
+		return fmt.Errorf("%w: %d ops", ErrWriteBehindDropped, dropped)
+	}
+	return nil
+}
+
+// Close shuts the cache down for good at process exit: it stops
+// auto-reload and the janitor and drains the WithWriteBehind queue, exactly
+// as StopAndWait does, returning its error. Writes made after Close are
+// still mirrored, restarting the flush goroutine, so stop writing first.
+func (c *Cache[T]) Close(ctx context.Context) error {
+	return c.StopAndWait(ctx)
+}
+
+// WriteBehindPending returns the number of WithWriteBehind ops waiting for
+// the sink.
+func (c *Cache[T]) WriteBehindPending() int {
+	w := c.wb
+	if w == nil {
+		return 0
+	}
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return len(w.ops)
+}
@@ -0,0 +1,188 @@
+package cache_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/TheOrchestraX/cache"
+	"github.com/TheOrchestraX/cache/cachetest"
+)
+
+// recordingSink is a write-behind sink that records the ops it accepts and
+// fails the first failures calls.
+type recordingSink struct {
+	mu       sync.Mutex
+	ops      []cache.Op[int]
+	calls    int
+	failures int
+}
+
+func (s *recordingSink) write(ctx context.Context, ops []cache.Op[int]) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.calls++
+	if s.calls <= s.failures {
+		return errors.New("sink unavailable")
+	}
+	s.ops = append(s.ops, ops...)
+	return nil
+}
+
+func (s *recordingSink) snapshot() (ops []cache.Op[int], calls int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return slices.Clone(s.ops), s.calls
+}
+
+// newWriteBehindCache returns an empty, silent cache on a fake clock
+// mirroring its writes to sink.
+func newWriteBehindCache(sink *recordingSink, opts ...cache.Option[int]) (*cache.Cache[int], *cachetest.FakeClock) {
+	clk := cachetest.NewFakeClock(epoch)
+	opts = append([]cache.Option[int]{
+		cache.WithClock[int](clk),
+		cache.WithLogLevel[int](cache.LogSilent),
+		cache.WithWriteBehind(sink.write, time.Second, 10),
+	}, opts...)
+	return cache.NewCache(func() (map[string]int, error) { return map[string]int{}, nil }, 0, opts...), clk
+}
+
+func TestWriteBehindOrderPerKey(t *testing.T) {
+	sink := &recordingSink{}
+	c, _ := newWriteBehindCache(sink, cache.WithWriteBehindBuffer[int](1<<16, cache.BlockWhenFull))
+	const writers, writes = 8, 200
+	var wg sync.WaitGroup
+	for w := range writers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range writes {
+				if i%10 == 9 {
+					c.Delete("shared")
+				} else {
+					c.Add("shared", w*writes+i)
+				}
+				c.Add(fmt.Sprintf("own%d", w), i)
+			}
+		}()
+	}
+	wg.Wait()
+	if err := c.Close(context.Background()); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+	ops, _ := sink.snapshot()
+	if len(ops) != 2*writers*writes {
+		t.Fatalf("sink got %d ops, want %d", len(ops), 2*writers*writes)
+	}
+	// Replaying the ops must reproduce the cache, and each writer's own
+	// key must see its writes in order.
+	replayed := make(map[string]int)
+	last := make(map[string]int)
+	for _, op := range ops {
+		switch op.Kind {
+		case cache.OpSet:
+			if prev, ok := last[op.Key]; ok && op.Key != "shared" && op.Value != prev+1 {
+				t.Fatalf("%s: set %d after %d", op.Key, op.Value, prev)
+			}
+			last[op.Key] = op.Value
+			replayed[op.Key] = op.Value
+		case cache.OpDelete:
+			delete(replayed, op.Key)
+		}
+	}
+	v, ok := c.Get("shared")
+	if got, gotOK := replayed["shared"]; got != v || gotOK != ok {
+		t.Fatalf("replayed shared = %d, %v; cache holds %d, %v", got, gotOK, v, ok)
+	}
+}
+
+func TestWriteBehindRetriesFlakySink(t *testing.T) {
+	sink := &recordingSink{failures: 2}
+	c, clk := newWriteBehindCache(sink)
+	c.Add("a", 1)
+	c.Delete("a")
+	c.Add("b", 2)
+
+	// The flush goroutine waits one interval, then backs off 2s and 4s.
+	for i, wait := range []time.Duration{time.Second, 2 * time.Second, 4 * time.Second} {
+		waitFor(t, "the flush timer", func() bool {
+			_, calls := sink.snapshot()
+			return calls == i && clk.Waiters() == 1
+		})
+		clk.Advance(wait)
+	}
+	waitFor(t, "the queue to drain", func() bool { return c.WriteBehindPending() == 0 })
+
+	ops, calls := sink.snapshot()
+	want := []cache.Op[int]{{Kind: cache.OpSet, Key: "a", Value: 1}, {Kind: cache.OpDelete, Key: "a"}, {Kind: cache.OpSet, Key: "b", Value: 2}}
+	if !slices.Equal(ops, want) || calls != 3 {
+		t.Fatalf("sink got %v in %d calls, want %v in 3", ops, calls, want)
+	}
+	if n := c.Stats().WriteBehindErrors; n != 2 {
+		t.Fatalf("Stats().WriteBehindErrors = %d, want 2", n)
+	}
+}
+
+func TestWriteBehindFlushKeepsFailedBatch(t *testing.T) {
+	sink := &recordingSink{failures: 1}
+	c, _ := newWriteBehindCache(sink)
+	c.Add("a", 1)
+	if err := c.FlushWriteBehind(context.Background()); err == nil {
+		t.Fatal("FlushWriteBehind with a failing sink succeeded")
+	}
+	if n := c.WriteBehindPending(); n != 1 {
+		t.Fatalf("WriteBehindPending = %d after a failed flush, want 1", n)
+	}
+	if err := c.FlushWriteBehind(context.Background()); err != nil {
+		t.Fatalf("second FlushWriteBehind: %v", err)
+	}
+	if ops, _ := sink.snapshot(); len(ops) != 1 {
+		t.Fatalf("sink got %v, want the one set", ops)
+	}
+}
+
+func TestWriteBehindDropWhenFull(t *testing.T) {
+	sink := &recordingSink{}
+	c, _ := newWriteBehindCache(sink, cache.WithWriteBehindBuffer[int](2, cache.DropWhenFull))
+	c.Add("a", 1)
+	c.Add("b", 2)
+	c.Add("c", 3) // no flush has run on the fake clock, so this op drops
+	mustGet(t, c, "c")
+	if err := c.FlushWriteBehind(context.Background()); !errors.Is(err, cache.ErrWriteBehindDropped) {
+		t.Fatalf("FlushWriteBehind = %v, want ErrWriteBehindDropped", err)
+	}
+	if ops, _ := sink.snapshot(); len(ops) != 2 {
+		t.Fatalf("sink got %v, want the first two sets", ops)
+	}
+	if n := c.Stats().WriteBehindDropped; n != 1 {
+		t.Fatalf("Stats().WriteBehindDropped = %d, want 1", n)
+	}
+	if err := c.FlushWriteBehind(context.Background()); err != nil {
+		t.Fatalf("drops reported twice: %v", err)
+	}
+}
+
+func TestWriteBehindBlockWhenFull(t *testing.T) {
+	sink := &recordingSink{}
+	c, _ := newWriteBehindCache(sink, cache.WithWriteBehindBuffer[int](1, cache.BlockWhenFull))
+	c.Add("a", 1)
+	done := make(chan struct{})
+	go func() {
+		c.Add("b", 2)
+		close(done)
+	}()
+	waitFor(t, "the second op to queue", func() bool { return c.WriteBehindPending() == 2 })
+	select {
+	case <-done:
+		t.Fatal("Add returned with the buffer over its bound")
+	default:
+	}
+	if err := c.FlushWriteBehind(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	<-done
+}